@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
 	"courseworker/config"
 	"courseworker/internal/handler"
+	"courseworker/pkg/jobs"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"sync"
+	"syscall"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
@@ -67,8 +75,32 @@ func main() {
 	defer db.Close()
 	defer rdc.Close()
 
+	blob, err := config.NewBlobStorage()
+	if err != nil {
+		log.Fatalf("Blob storage initialization error: %v", err)
+	}
+
+	providers, err := config.NewOAuthRegistry()
+	if err != nil {
+		log.Fatalf("OAuth provider initialization error: %v", err)
+	}
+
+	mail, err := config.NewMailer()
+	if err != nil {
+		log.Fatalf("Mailer initialization error: %v", err)
+	}
+
+	queue := jobs.NewQueue(rdc, jobs.Options{})
+
 	r := gin.Default()
-	handler.StartEngine(r, db, rdc)
+	if err := r.SetTrustedProxies(config.TrustedProxies()); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	handler.StartEngine(r, db, rdc, blob, providers, queue)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	workersDone := handler.StartWorkers(ctx, db, rdc, blob, queue, mail)
 
 	port := os.Getenv("APP_PORT")
 	if port == "" {
@@ -76,7 +108,26 @@ func main() {
 		log.Print("No APP_PORT found, using default: 8000")
 	}
 
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := &http.Server{Addr: ":" + port, Handler: r}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Print("Shutting down")
+
+	// Stop taking requests first, then let the job workers finish what they
+	// are running before the database and Redis connections are closed.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown error: %v", err)
+	}
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		log.Print("Timed out waiting for job workers")
 	}
 }
@@ -2,17 +2,24 @@ package config
 
 import (
 	"context"
+	"courseworker/pkg/mailer"
+	"courseworker/pkg/oauth"
+	"courseworker/pkg/storage"
 	"database/sql"
 	"fmt"
 	"log"
 	"os"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/redis/go-redis/v9"
 )
 
 func SetupDB() (*sql.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True",
+	// Timestamps are stored and read as UTC; converting to a user's zone
+	// happens when rendering responses.
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=UTC&time_zone=%%27%%2B00%%3A00%%27",
 		os.Getenv("DB_USER"),
 		os.Getenv("DB_PASS"),
 		os.Getenv("DB_HOST"),
@@ -52,3 +59,118 @@ func NewRedisClient() (*redis.Client, error) {
 
 	return rdc, nil
 }
+
+func NewBlobStorage() (storage.Blob, error) {
+	switch os.Getenv("STORAGE_DRIVER") {
+	case "s3":
+		useSSL, _ := strconv.ParseBool(os.Getenv("S3_USE_SSL"))
+		return storage.NewS3(context.Background(), storage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Region:    os.Getenv("S3_REGION"),
+			UseSSL:    useSSL,
+		})
+	case "", "local":
+		dir := os.Getenv("STORAGE_LOCAL_DIR")
+		if dir == "" {
+			dir = "./uploads"
+		}
+		return storage.NewLocal(dir, os.Getenv("BASE_URL"), []byte(os.Getenv("JWT_SECRET_KEY")))
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q", os.Getenv("STORAGE_DRIVER"))
+	}
+}
+
+// NewMailer sets up email delivery from MAIL_DRIVER: "smtp" (the default)
+// sends through SMTP_HOST and friends, "outbox" keeps the emails and writes
+// them as .eml files to MAIL_OUTBOX_DIR when set.
+func NewMailer() (mailer.Mailer, error) {
+	from := mailer.From{Name: os.Getenv("FROM_NAME"), Email: os.Getenv("FROM_EMAIL")}
+	switch os.Getenv("MAIL_DRIVER") {
+	case "", "smtp":
+		port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		if err != nil {
+			return nil, fmt.Errorf("SMTP_PORT must be a number: %w", err)
+		}
+		return mailer.NewSMTP(mailer.SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     port,
+			User:     os.Getenv("SMTP_USER"),
+			Password: os.Getenv("SMTP_PASS"),
+			From:     from,
+		}), nil
+	case "outbox":
+		return mailer.NewOutbox(os.Getenv("MAIL_OUTBOX_DIR"), from)
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q", os.Getenv("MAIL_DRIVER"))
+	}
+}
+
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9-]{1,32}$`)
+
+// NewOAuthRegistry reads the login providers listed in OIDC_PROVIDERS. Each
+// provider is configured through OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID,
+// OIDC_<NAME>_CLIENT_SECRET and optionally OIDC_<NAME>_SCOPES, where <NAME> is
+// the upper-cased provider name with dashes turned into underscores. Setting
+// OIDC_<NAME>_TRUST_EMAIL=true lets logins through the provider link to an
+// existing account by verified email; it is off unless set. Google
+// defaults to its public issuer and the legacy CLIENT_ID/CLIENT_SECRET, and is
+// enabled on its own when only those are set.
+func NewOAuthRegistry() (*oauth.Registry, error) {
+	list := os.Getenv("OIDC_PROVIDERS")
+	if list == "" && os.Getenv("CLIENT_ID") != "" {
+		list = "google"
+	}
+
+	var configs []oauth.Config
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !providerNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid OIDC provider name %q", name)
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		cfg := oauth.Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("BASE_URL") + "/auth/" + name + "/callback",
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+			TrustEmail:   os.Getenv(prefix+"TRUST_EMAIL") == "true",
+		}
+		if name == "google" {
+			if cfg.Issuer == "" {
+				cfg.Issuer = "https://accounts.google.com"
+			}
+			if cfg.ClientID == "" {
+				cfg.ClientID = os.Getenv("CLIENT_ID")
+				cfg.ClientSecret = os.Getenv("CLIENT_SECRET")
+			}
+		}
+		if cfg.Issuer == "" || cfg.ClientID == "" {
+			return nil, fmt.Errorf("OIDC provider %s needs %sISSUER and %sCLIENT_ID", name, prefix, prefix)
+		}
+		configs = append(configs, cfg)
+	}
+	return oauth.NewRegistry(configs), nil
+}
+
+// TrustedProxies lists the comma separated addresses or CIDRs of
+// TRUSTED_PROXIES. Only requests from these may set the client IP through
+// X-Forwarded-For, which the rate limits key on; when unset no proxy is
+// trusted.
+func TrustedProxies() []string {
+	var proxies []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
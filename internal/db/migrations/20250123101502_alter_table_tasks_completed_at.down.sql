@@ -0,0 +1,2 @@
+ALTER TABLE tasks
+    DROP COLUMN completed_at;
\ No newline at end of file
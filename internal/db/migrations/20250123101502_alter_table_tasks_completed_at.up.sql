@@ -0,0 +1,2 @@
+ALTER TABLE tasks
+    ADD COLUMN completed_at TIMESTAMP NULL DEFAULT NULL AFTER highlight;
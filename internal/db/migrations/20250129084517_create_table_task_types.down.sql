@@ -0,0 +1 @@
+DROP TABLE IF EXISTS task_types;
\ No newline at end of file
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS task_types (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    name VARCHAR(20) NOT NULL, -- as wide as tasks.type
+    color CHAR(7) NOT NULL DEFAULT '#64748B',
+    reminder_offsets VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_task_type_user_name (user_id, name),
+    CONSTRAINT fk_user_task_type FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
+
+INSERT INTO task_types (user_id, name, color, reminder_offsets)
+SELECT u.id, d.name, d.color, d.reminder_offsets FROM users u
+CROSS JOIN (
+    SELECT 'assignment' AS name, '#2563EB' AS color, '4320,1440,120' AS reminder_offsets
+    UNION ALL SELECT 'quiz', '#16A34A', '1440,120'
+    UNION ALL SELECT 'exam', '#DC2626', '10080,4320,1440'
+    UNION ALL SELECT 'group project', '#9333EA', '10080,4320,1440'
+    UNION ALL SELECT 'reading', '#CA8A04', '1440'
+) d;
+
+-- Every type already in use becomes a type of its owner. name is as wide as
+-- tasks.type, so the values are copied unchanged; anything that does not fit
+-- fails the migration instead of being truncated.
+INSERT INTO task_types (user_id, name)
+SELECT DISTINCT c.user_id, t.type FROM tasks t
+INNER JOIN courses c ON t.course_id = c.id
+WHERE NOT EXISTS (
+    SELECT 1 FROM task_types tt WHERE tt.user_id = c.user_id AND tt.name = t.type
+);
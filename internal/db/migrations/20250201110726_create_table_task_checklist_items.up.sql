@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS task_checklist_items (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    task_id CHAR(36) NOT NULL,
+    `text` VARCHAR(255) NOT NULL,
+    is_done TINYINT(1) NOT NULL DEFAULT FALSE,
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_checklist_task_position (task_id, position),
+    CONSTRAINT fk_checklist_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+    DROP FOREIGN KEY fk_series_task,
+    DROP COLUMN series_id;
+
+DROP TABLE IF EXISTS task_series;
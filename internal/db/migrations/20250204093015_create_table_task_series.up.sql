@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS task_series (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    rrule VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_series_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
+
+ALTER TABLE tasks
+    ADD COLUMN series_id CHAR(36) NULL DEFAULT NULL AFTER completed_at,
+    ADD CONSTRAINT fk_series_task FOREIGN KEY (series_id) REFERENCES task_series(id) ON DELETE CASCADE;
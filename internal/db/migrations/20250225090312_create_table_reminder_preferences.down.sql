@@ -0,0 +1,3 @@
+DROP INDEX idx_task_deadline ON tasks;
+
+DROP TABLE IF EXISTS reminder_preferences;
\ No newline at end of file
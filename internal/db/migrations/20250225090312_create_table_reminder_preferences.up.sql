@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS reminder_preferences (
+    user_id CHAR(36) NOT NULL PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    default_offsets VARCHAR(255) NOT NULL DEFAULT '1440,120',
+    channels VARCHAR(64) NOT NULL DEFAULT 'email,in_app',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_reminder_pref_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
+
+CREATE INDEX idx_task_deadline ON tasks (is_done, deadline);
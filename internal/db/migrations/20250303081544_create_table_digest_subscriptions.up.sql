@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+    user_id CHAR(36) NOT NULL PRIMARY KEY,
+    frequency VARCHAR(8) NOT NULL DEFAULT 'off',
+    send_hour TINYINT NOT NULL DEFAULT 7,
+    weekday TINYINT NOT NULL DEFAULT 1,
+    last_sent_at TIMESTAMP NULL DEFAULT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_digest_frequency (frequency),
+    CONSTRAINT fk_digest_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
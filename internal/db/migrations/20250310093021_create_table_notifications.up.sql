@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    `type` VARCHAR(32) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body VARCHAR(1000) NOT NULL DEFAULT '',
+    course_id BIGINT NULL DEFAULT NULL,
+    task_id CHAR(36) NULL DEFAULT NULL,
+    link VARCHAR(500) NOT NULL DEFAULT '',
+    read_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_notification_user (user_id, id),
+    INDEX idx_notification_unread (user_id, read_at),
+    CONSTRAINT fk_notification_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_notification_course FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_notification_task FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
@@ -0,0 +1,13 @@
+-- name: GetUsageStats :one
+SELECT
+    (SELECT COUNT(1) FROM users) AS total_users,
+    (SELECT COUNT(1) FROM users WHERE role = 'admin') AS admin_users,
+    (SELECT COUNT(1) FROM users WHERE disabled_at IS NOT NULL) AS disabled_users,
+    (SELECT COUNT(1) FROM users WHERE created_at >= NOW() - INTERVAL 30 DAY) AS new_users,
+    (SELECT COUNT(1) FROM courses) AS total_courses,
+    (SELECT COUNT(1) FROM tasks) AS total_tasks,
+    (SELECT COUNT(1) FROM tasks WHERE is_done = TRUE) AS done_tasks,
+    (SELECT COUNT(1) FROM task_attachments) AS total_attachments,
+    (SELECT CAST(COALESCE(SUM(b.size), 0) AS SIGNED) FROM (
+        SELECT DISTINCT blob_key, size FROM task_attachments
+    ) b) AS storage_bytes;
@@ -17,4 +17,18 @@ WHERE id = ?;
 DELETE FROM courses WHERE id = ?;
 
 -- name: GetUserIDFromCourse :one
-SELECT user_id FROM courses WHERE id = ?;
\ No newline at end of file
+SELECT user_id FROM courses WHERE id = ?;
+
+-- name: GetTaskIDsByCourse :many
+SELECT id FROM tasks WHERE course_id = ?;
+
+-- name: GetCourseIDsByUser :many
+SELECT id FROM courses WHERE user_id = ?;
+
+-- name: GetTaskIDsByUser :many
+SELECT t.id FROM tasks t
+INNER JOIN courses c ON t.course_id = c.id
+WHERE c.user_id = ?;
+
+-- name: DeleteCoursesByUser :execresult
+DELETE FROM courses WHERE user_id = ?;
@@ -0,0 +1,23 @@
+-- name: GetDigestSubscription :one
+SELECT * FROM digest_subscriptions WHERE user_id = ?;
+
+-- name: GetActiveDigestSubscriptions :many
+SELECT ds.user_id, ds.frequency, ds.send_hour, ds.weekday, ds.last_sent_at,
+       u.name, u.email, u.timezone, u.locale
+FROM digest_subscriptions ds
+INNER JOIN users u ON ds.user_id = u.id
+WHERE ds.frequency <> 'off' AND u.disabled_at IS NULL;
+
+-- name: UpsertDigestSubscription :execresult
+INSERT INTO digest_subscriptions (user_id, frequency, send_hour, weekday)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    frequency = VALUES(frequency),
+    send_hour = VALUES(send_hour),
+    weekday = VALUES(weekday);
+
+-- name: UnsubscribeDigest :execresult
+UPDATE digest_subscriptions SET frequency = 'off' WHERE user_id = ?;
+
+-- name: SetDigestSentAt :execresult
+UPDATE digest_subscriptions SET last_sent_at = ? WHERE user_id = ?;
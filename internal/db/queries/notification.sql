@@ -0,0 +1,32 @@
+-- name: GetNotificationsByUserID :many
+SELECT * FROM notifications
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.arg(unread_only) = FALSE OR read_at IS NULL)
+ORDER BY id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CountNotificationsByUserID :one
+SELECT COUNT(1) FROM notifications
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.arg(unread_only) = FALSE OR read_at IS NULL);
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(1) FROM notifications WHERE user_id = ? AND read_at IS NULL;
+
+-- name: GetNotificationByID :one
+SELECT * FROM notifications WHERE id = ? AND user_id = ?;
+
+-- name: CreateNotification :execresult
+INSERT INTO notifications (user_id, type, title, body, course_id, task_id, link)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: MarkNotificationRead :execresult
+UPDATE notifications SET read_at = CURRENT_TIMESTAMP
+WHERE id = ? AND user_id = ? AND read_at IS NULL;
+
+-- name: MarkAllNotificationsRead :execresult
+UPDATE notifications SET read_at = CURRENT_TIMESTAMP
+WHERE user_id = ? AND read_at IS NULL;
+
+-- name: DeleteNotification :execresult
+DELETE FROM notifications WHERE id = ? AND user_id = ?;
@@ -0,0 +1,18 @@
+-- name: GetPersonalAccessTokenByHash :one
+SELECT * FROM personal_access_tokens WHERE token_hash = ?;
+
+-- name: GetPersonalAccessTokensByUserID :many
+SELECT * FROM personal_access_tokens WHERE user_id = ? ORDER BY created_at DESC;
+
+-- name: CreatePersonalAccessToken :execresult
+INSERT INTO personal_access_tokens (id, user_id, name, token_hash, scopes, expires_at)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: TouchPersonalAccessToken :execresult
+UPDATE personal_access_tokens SET last_used_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: DeletePersonalAccessToken :execresult
+DELETE FROM personal_access_tokens WHERE id = ? AND user_id = ?;
+
+-- name: DeletePersonalAccessTokensByUserID :execresult
+DELETE FROM personal_access_tokens WHERE user_id = ?;
@@ -0,0 +1,27 @@
+-- name: GetReminderPreferences :one
+SELECT * FROM reminder_preferences WHERE user_id = ?;
+
+-- name: UpsertReminderPreferences :execresult
+INSERT INTO reminder_preferences (user_id, enabled, default_offsets, channels)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    enabled = VALUES(enabled),
+    default_offsets = VALUES(default_offsets),
+    channels = VALUES(channels);
+
+-- name: GetUpcomingDeadlines :many
+SELECT t.id, t.title, t.deadline, t.course_id, c.name AS course_name,
+       u.id AS user_id, u.name AS user_name, u.email, u.timezone, u.locale,
+       COALESCE(tt.reminder_offsets, '') AS type_offsets,
+       COALESCE(rp.default_offsets, '1440,120') AS default_offsets,
+       COALESCE(rp.channels, 'email,in_app') AS channels
+FROM tasks t
+INNER JOIN courses c ON t.course_id = c.id
+INNER JOIN users u ON c.user_id = u.id
+LEFT JOIN task_types tt ON tt.user_id = u.id AND tt.name = t.type
+LEFT JOIN reminder_preferences rp ON rp.user_id = u.id
+WHERE t.is_done = FALSE
+  AND t.deadline > sqlc.arg(from_time) AND t.deadline <= sqlc.arg(to_time)
+  AND u.disabled_at IS NULL
+  AND (rp.enabled IS NULL OR rp.enabled = TRUE)
+ORDER BY t.deadline;
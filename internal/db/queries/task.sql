@@ -9,18 +9,21 @@ SELECT * FROM tasks WHERE course_id = ?;
 -- name: GetTaskByID :one
 SELECT * FROM tasks WHERE id = ?;
 
+-- name: GetTasksBySeriesID :many
+SELECT * FROM tasks WHERE series_id = ? ORDER BY deadline, id;
+
 -- name: GetUserIDFromTask :one
 SELECT c.user_id FROM courses c
 INNER JOIN tasks t ON t.course_id = c.id
 WHERE t.id = sqlc.arg(task_id) AND c.id = sqlc.arg(course_id);
 
 -- name: CreateTask :execresult
-INSERT INTO tasks (id, course_id, title, type, description, deadline)
-VALUES (?, ?, ?, ?, ?, ?);
+INSERT INTO tasks (id, course_id, title, type, description, deadline, series_id)
+VALUES (?, ?, ?, ?, ?, ?, ?);
 
 -- name: UpdateTask :execresult
 UPDATE tasks
-SET title = ?, type = ?, description = ?, deadline = ?
+SET course_id = ?, title = ?, type = ?, description = ?, deadline = ?
 WHERE id = ?;
 
 -- name: AddImage :execresult
@@ -32,5 +35,8 @@ UPDATE tasks SET image = NULL WHERE id = ?;
 -- name: SwitchTaskHighlight :execresult
 UPDATE tasks SET highlight = ? WHERE id = ?;
 
+-- name: SetTaskDone :execresult
+UPDATE tasks SET is_done = ?, completed_at = ? WHERE id = ?;
+
 -- name: DeleteTask :execresult
 DELETE FROM tasks WHERE id = ?;
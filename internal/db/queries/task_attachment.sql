@@ -0,0 +1,27 @@
+-- name: GetAttachmentsByTaskIDs :many
+SELECT * FROM task_attachments
+WHERE task_id IN (sqlc.slice(task_ids))
+ORDER BY created_at, id;
+
+-- name: GetAttachmentByID :one
+SELECT * FROM task_attachments WHERE id = ? AND task_id = ?;
+
+-- name: GetAttachmentBlobKeysByCourse :many
+SELECT DISTINCT a.blob_key FROM task_attachments a
+INNER JOIN tasks t ON a.task_id = t.id
+WHERE t.course_id = ?;
+
+-- name: CountAttachmentsByBlobKey :one
+SELECT COUNT(1) FROM task_attachments WHERE blob_key = ?;
+
+-- name: GetUserStorageUsage :one
+SELECT CAST(COALESCE(SUM(b.size), 0) AS SIGNED) FROM (
+    SELECT DISTINCT blob_key, size FROM task_attachments WHERE user_id = ?
+) b;
+
+-- name: CreateAttachment :execresult
+INSERT INTO task_attachments (task_id, user_id, filename, content_type, size, checksum, blob_key)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: DeleteAttachment :execresult
+DELETE FROM task_attachments WHERE id = ? AND task_id = ?;
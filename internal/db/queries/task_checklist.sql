@@ -0,0 +1,31 @@
+-- name: GetChecklistItemsByTaskID :many
+SELECT * FROM task_checklist_items
+WHERE task_id = ?
+ORDER BY position, id;
+
+-- name: GetChecklistItemByID :one
+SELECT * FROM task_checklist_items WHERE id = ? AND task_id = ?;
+
+-- name: GetMaxChecklistPosition :one
+SELECT CAST(COALESCE(MAX(position), 0) AS SIGNED) FROM task_checklist_items WHERE task_id = ?;
+
+-- name: GetChecklistProgressByTaskIDs :many
+SELECT task_id, COUNT(1) AS total, CAST(SUM(is_done) AS SIGNED) AS done
+FROM task_checklist_items
+WHERE task_id IN (sqlc.slice(task_ids))
+GROUP BY task_id;
+
+-- name: CreateChecklistItem :execresult
+INSERT INTO task_checklist_items (task_id, text, position)
+VALUES (?, ?, ?);
+
+-- name: UpdateChecklistItem :execresult
+UPDATE task_checklist_items
+SET text = ?, is_done = ?
+WHERE id = ? AND task_id = ?;
+
+-- name: UpdateChecklistItemPosition :execresult
+UPDATE task_checklist_items SET position = ? WHERE id = ? AND task_id = ?;
+
+-- name: DeleteChecklistItem :execresult
+DELETE FROM task_checklist_items WHERE id = ? AND task_id = ?;
@@ -0,0 +1,26 @@
+-- name: GetNotesByTaskID :many
+SELECT * FROM task_notes
+WHERE task_id = ?
+ORDER BY created_at, id
+LIMIT ? OFFSET ?;
+
+-- name: GetAllNotesByTaskID :many
+SELECT * FROM task_notes
+WHERE task_id = ?
+ORDER BY created_at, id;
+
+-- name: CountNotesByTaskID :one
+SELECT COUNT(1) FROM task_notes WHERE task_id = ?;
+
+-- name: GetNoteByID :one
+SELECT * FROM task_notes WHERE id = ? AND task_id = ?;
+
+-- name: CreateNote :execresult
+INSERT INTO task_notes (task_id, text)
+VALUES (?, ?);
+
+-- name: UpdateNote :execresult
+UPDATE task_notes SET text = ? WHERE id = ? AND task_id = ?;
+
+-- name: DeleteNote :execresult
+DELETE FROM task_notes WHERE id = ? AND task_id = ?;
@@ -0,0 +1,15 @@
+-- name: GetTaskSeriesByID :one
+SELECT * FROM task_series WHERE id = ?;
+
+-- name: CreateTaskSeries :execresult
+INSERT INTO task_series (id, user_id, rrule)
+VALUES (?, ?, ?);
+
+-- name: DeleteEmptyTaskSeries :execresult
+DELETE FROM task_series
+WHERE id = ?
+  AND NOT EXISTS (SELECT 1 FROM tasks t WHERE t.series_id = task_series.id);
+
+-- name: GetTaskSeriesIDsByCourse :many
+SELECT DISTINCT series_id FROM tasks
+WHERE course_id = ? AND series_id IS NOT NULL;
@@ -0,0 +1,31 @@
+-- name: GetTaskTypesByUser :many
+SELECT * FROM task_types WHERE user_id = ? ORDER BY id;
+
+-- name: GetTaskTypeByID :one
+SELECT * FROM task_types WHERE id = ? AND user_id = ?;
+
+-- name: GetTaskTypeByName :one
+SELECT * FROM task_types WHERE user_id = ? AND name = ?;
+
+-- name: CreateTaskType :execresult
+INSERT INTO task_types (user_id, name, color, reminder_offsets)
+VALUES (?, ?, ?, ?);
+
+-- name: UpdateTaskType :execresult
+UPDATE task_types
+SET name = ?, color = ?, reminder_offsets = ?
+WHERE id = ? AND user_id = ?;
+
+-- name: DeleteTaskType :execresult
+DELETE FROM task_types WHERE id = ? AND user_id = ?;
+
+-- name: CountTasksOfType :one
+SELECT COUNT(1) FROM tasks t
+INNER JOIN courses c ON t.course_id = c.id
+WHERE c.user_id = sqlc.arg(user_id) AND t.type = sqlc.arg(type);
+
+-- name: RenameTypeOfTasks :execresult
+UPDATE tasks t
+INNER JOIN courses c ON t.course_id = c.id
+SET t.type = sqlc.arg(new_type)
+WHERE c.user_id = sqlc.arg(user_id) AND t.type = sqlc.arg(old_type);
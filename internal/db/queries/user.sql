@@ -1,10 +1,10 @@
--- name: GetAllUsers :many
-SELECT id, name, email, profile_img, created_at, updated_at FROM users;
-
 -- name: GetUserByID :one
 SELECT * FROM users
 WHERE id = ?;
 
+-- name: GetUserTimezone :one
+SELECT timezone FROM users WHERE id = ?;
+
 -- name: CountUserByEmail :one
 SELECT COUNT(1) FROM users WHERE email = ?;
 
@@ -12,5 +12,34 @@ SELECT COUNT(1) FROM users WHERE email = ?;
 SELECT * FROM users WHERE email = ?;
 
 -- name: CreateUser :execresult
-INSERT INTO users (id, name, email, password)
-VALUES (?, ?, ?, ?);
\ No newline at end of file
+INSERT INTO users (id, name, email, password, locale)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: UpdateUserTimezone :execresult
+UPDATE users SET timezone = ? WHERE id = ?;
+
+-- name: UpdateUserPassword :execresult
+UPDATE users SET password = ? WHERE id = ?;
+
+-- name: UpdateUserProfile :execresult
+UPDATE users SET name = ?, timezone = ?, locale = ? WHERE id = ?;
+
+-- name: UpdateUserEmail :execresult
+UPDATE users SET email = ? WHERE id = ?;
+
+-- name: UpdateUserProfileImg :execresult
+UPDATE users SET profile_img = ? WHERE id = ?;
+
+
+-- name: UpdateUserRole :execresult
+UPDATE users SET role = ? WHERE id = ?;
+
+-- name: UpdateUserRoleByEmails :execresult
+UPDATE users SET role = sqlc.arg(role) WHERE email IN (sqlc.slice(emails));
+
+-- name: SetUserDisabledAt :execresult
+UPDATE users SET disabled_at = ? WHERE id = ?;
+
+
+-- name: DeleteUser :execresult
+DELETE FROM users WHERE id = ?;
@@ -0,0 +1,12 @@
+-- name: GetIdentity :one
+SELECT * FROM user_identities WHERE provider = ? AND subject = ?;
+
+-- name: GetIdentitiesByUserID :many
+SELECT * FROM user_identities WHERE user_id = ? ORDER BY created_at;
+
+-- name: CreateIdentity :execresult
+INSERT INTO user_identities (user_id, provider, subject, email)
+VALUES (?, ?, ?, ?);
+
+-- name: DeleteIdentity :execresult
+DELETE FROM user_identities WHERE user_id = ? AND provider = ?;
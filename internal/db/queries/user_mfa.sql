@@ -0,0 +1,30 @@
+-- name: GetUserMFA :one
+SELECT * FROM user_mfa WHERE user_id = ?;
+
+-- name: UpsertUserMFA :execresult
+INSERT INTO user_mfa (user_id, totp_secret)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE totp_secret = VALUES(totp_secret), enabled_at = NULL;
+
+-- name: EnableUserMFA :execresult
+UPDATE user_mfa SET enabled_at = NOW() WHERE user_id = ?;
+
+-- name: DeleteUserMFA :execresult
+DELETE FROM user_mfa WHERE user_id = ?;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(1) FROM user_recovery_codes WHERE user_id = ? AND used_at IS NULL;
+
+-- name: GetUnusedRecoveryCodes :many
+SELECT * FROM user_recovery_codes WHERE user_id = ? AND used_at IS NULL;
+
+-- name: CreateRecoveryCode :execresult
+INSERT INTO user_recovery_codes (user_id, code_hash)
+VALUES (?, ?);
+
+-- name: UseRecoveryCode :execresult
+UPDATE user_recovery_codes SET used_at = NOW()
+WHERE id = ? AND user_id = ? AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :execresult
+DELETE FROM user_recovery_codes WHERE user_id = ?;
@@ -33,6 +33,14 @@ func (q *Queries) DeleteCourse(ctx context.Context, id int64) (sql.Result, error
 	return q.db.ExecContext(ctx, deleteCourse, id)
 }
 
+const deleteCoursesByUser = `-- name: DeleteCoursesByUser :execresult
+DELETE FROM courses WHERE user_id = ?
+`
+
+func (q *Queries) DeleteCoursesByUser(ctx context.Context, userID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteCoursesByUser, userID)
+}
+
 const getAllCourses = `-- name: GetAllCourses :many
 SELECT id, name, subname, user_id, created_at, updated_at FROM courses WHERE user_id = ?
 `
@@ -85,6 +93,89 @@ func (q *Queries) GetCourseByID(ctx context.Context, id int64) (Course, error) {
 	return i, err
 }
 
+const getCourseIDsByUser = `-- name: GetCourseIDsByUser :many
+SELECT id FROM courses WHERE user_id = ?
+`
+
+func (q *Queries) GetCourseIDsByUser(ctx context.Context, userID string) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getCourseIDsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTaskIDsByCourse = `-- name: GetTaskIDsByCourse :many
+SELECT id FROM tasks WHERE course_id = ?
+`
+
+func (q *Queries) GetTaskIDsByCourse(ctx context.Context, courseID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getTaskIDsByCourse, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTaskIDsByUser = `-- name: GetTaskIDsByUser :many
+SELECT t.id FROM tasks t
+INNER JOIN courses c ON t.course_id = c.id
+WHERE c.user_id = ?
+`
+
+func (q *Queries) GetTaskIDsByUser(ctx context.Context, userID string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getTaskIDsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserIDFromCourse = `-- name: GetUserIDFromCourse :one
 SELECT user_id FROM courses WHERE id = ?
 `
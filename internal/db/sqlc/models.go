@@ -18,6 +18,47 @@ type Course struct {
 	UpdatedAt sql.NullTime
 }
 
+type DigestSubscription struct {
+	UserID     string
+	Frequency  string
+	SendHour   int32
+	Weekday    int32
+	LastSentAt sql.NullTime
+	UpdatedAt  time.Time
+}
+
+type Notification struct {
+	ID        int64
+	UserID    string
+	Type      string
+	Title     string
+	Body      string
+	CourseID  sql.NullInt64
+	TaskID    sql.NullString
+	Link      string
+	ReadAt    sql.NullTime
+	CreatedAt time.Time
+}
+
+type PersonalAccessToken struct {
+	ID         string
+	UserID     string
+	Name       string
+	TokenHash  string
+	Scopes     string
+	ExpiresAt  sql.NullTime
+	LastUsedAt sql.NullTime
+	CreatedAt  time.Time
+}
+
+type ReminderPreference struct {
+	UserID         string
+	Enabled        bool
+	DefaultOffsets string
+	Channels       string
+	UpdatedAt      time.Time
+}
+
 type Task struct {
 	ID          string
 	CourseID    int64
@@ -30,6 +71,30 @@ type Task struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	Highlight   bool
+	CompletedAt sql.NullTime
+	SeriesID    sql.NullString
+}
+
+type TaskAttachment struct {
+	ID          int64
+	TaskID      string
+	UserID      string
+	Filename    string
+	ContentType string
+	Size        int64
+	Checksum    string
+	BlobKey     string
+	CreatedAt   time.Time
+}
+
+type TaskChecklistItem struct {
+	ID        int64
+	TaskID    string
+	Text      string
+	IsDone    bool
+	Position  int32
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 type TaskNote struct {
@@ -40,6 +105,23 @@ type TaskNote struct {
 	UpdatedAt time.Time
 }
 
+type TaskSeries struct {
+	ID        string
+	UserID    string
+	Rrule     string
+	CreatedAt time.Time
+}
+
+type TaskType struct {
+	ID              int64
+	UserID          string
+	Name            string
+	Color           string
+	ReminderOffsets string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
 type User struct {
 	ID         string
 	Name       string
@@ -48,4 +130,32 @@ type User struct {
 	ProfileImg sql.NullString
 	CreatedAt  sql.NullTime
 	UpdatedAt  sql.NullTime
+	Timezone   string
+	Role       string
+	DisabledAt sql.NullTime
+	Locale     string
+}
+
+type UserIdentity struct {
+	ID        int64
+	UserID    string
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+type UserMfa struct {
+	UserID     string
+	TotpSecret string
+	EnabledAt  sql.NullTime
+	CreatedAt  time.Time
+}
+
+type UserRecoveryCode struct {
+	ID        int64
+	UserID    string
+	CodeHash  string
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
 }
@@ -24,8 +24,8 @@ func (q *Queries) AddImage(ctx context.Context, arg AddImageParams) (sql.Result,
 }
 
 const createTask = `-- name: CreateTask :execresult
-INSERT INTO tasks (id, course_id, title, type, description, deadline)
-VALUES (?, ?, ?, ?, ?, ?)
+INSERT INTO tasks (id, course_id, title, type, description, deadline, series_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateTaskParams struct {
@@ -35,6 +35,7 @@ type CreateTaskParams struct {
 	Type        string
 	Description sql.NullString
 	Deadline    sql.NullTime
+	SeriesID    sql.NullString
 }
 
 func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (sql.Result, error) {
@@ -45,6 +46,7 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (sql.Res
 		arg.Type,
 		arg.Description,
 		arg.Deadline,
+		arg.SeriesID,
 	)
 }
 
@@ -57,7 +59,7 @@ func (q *Queries) DeleteTask(ctx context.Context, id string) (sql.Result, error)
 }
 
 const getAllTasks = `-- name: GetAllTasks :many
-SELECT t.id, t.course_id, t.is_done, t.title, t.description, t.image, t.type, t.deadline, t.created_at, t.updated_at, t.highlight FROM tasks t
+SELECT t.id, t.course_id, t.is_done, t.title, t.description, t.image, t.type, t.deadline, t.created_at, t.updated_at, t.highlight, t.completed_at, t.series_id FROM tasks t
 INNER JOIN courses c ON t.course_id = c.id
 WHERE c.user_id = ?
 `
@@ -83,6 +85,8 @@ func (q *Queries) GetAllTasks(ctx context.Context, userID string) ([]Task, error
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Highlight,
+			&i.CompletedAt,
+			&i.SeriesID,
 		); err != nil {
 			return nil, err
 		}
@@ -98,7 +102,7 @@ func (q *Queries) GetAllTasks(ctx context.Context, userID string) ([]Task, error
 }
 
 const getTaskByID = `-- name: GetTaskByID :one
-SELECT id, course_id, is_done, title, description, image, type, deadline, created_at, updated_at, highlight FROM tasks WHERE id = ?
+SELECT id, course_id, is_done, title, description, image, type, deadline, created_at, updated_at, highlight, completed_at, series_id FROM tasks WHERE id = ?
 `
 
 func (q *Queries) GetTaskByID(ctx context.Context, id string) (Task, error) {
@@ -116,12 +120,14 @@ func (q *Queries) GetTaskByID(ctx context.Context, id string) (Task, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Highlight,
+		&i.CompletedAt,
+		&i.SeriesID,
 	)
 	return i, err
 }
 
 const getTasksByCourseID = `-- name: GetTasksByCourseID :many
-SELECT id, course_id, is_done, title, description, image, type, deadline, created_at, updated_at, highlight FROM tasks WHERE course_id = ?
+SELECT id, course_id, is_done, title, description, image, type, deadline, created_at, updated_at, highlight, completed_at, series_id FROM tasks WHERE course_id = ?
 `
 
 func (q *Queries) GetTasksByCourseID(ctx context.Context, courseID int64) ([]Task, error) {
@@ -145,6 +151,49 @@ func (q *Queries) GetTasksByCourseID(ctx context.Context, courseID int64) ([]Tas
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Highlight,
+			&i.CompletedAt,
+			&i.SeriesID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTasksBySeriesID = `-- name: GetTasksBySeriesID :many
+SELECT id, course_id, is_done, title, description, image, type, deadline, created_at, updated_at, highlight, completed_at, series_id FROM tasks WHERE series_id = ? ORDER BY deadline, id
+`
+
+func (q *Queries) GetTasksBySeriesID(ctx context.Context, seriesID sql.NullString) ([]Task, error) {
+	rows, err := q.db.QueryContext(ctx, getTasksBySeriesID, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.CourseID,
+			&i.IsDone,
+			&i.Title,
+			&i.Description,
+			&i.Image,
+			&i.Type,
+			&i.Deadline,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Highlight,
+			&i.CompletedAt,
+			&i.SeriesID,
 		); err != nil {
 			return nil, err
 		}
@@ -185,6 +234,20 @@ func (q *Queries) RemoveImage(ctx context.Context, id string) (sql.Result, error
 	return q.db.ExecContext(ctx, removeImage, id)
 }
 
+const setTaskDone = `-- name: SetTaskDone :execresult
+UPDATE tasks SET is_done = ?, completed_at = ? WHERE id = ?
+`
+
+type SetTaskDoneParams struct {
+	IsDone      bool
+	CompletedAt sql.NullTime
+	ID          string
+}
+
+func (q *Queries) SetTaskDone(ctx context.Context, arg SetTaskDoneParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, setTaskDone, arg.IsDone, arg.CompletedAt, arg.ID)
+}
+
 const switchTaskHighlight = `-- name: SwitchTaskHighlight :execresult
 UPDATE tasks SET highlight = ? WHERE id = ?
 `
@@ -200,11 +263,12 @@ func (q *Queries) SwitchTaskHighlight(ctx context.Context, arg SwitchTaskHighlig
 
 const updateTask = `-- name: UpdateTask :execresult
 UPDATE tasks
-SET title = ?, type = ?, description = ?, deadline = ?
+SET course_id = ?, title = ?, type = ?, description = ?, deadline = ?
 WHERE id = ?
 `
 
 type UpdateTaskParams struct {
+	CourseID    int64
 	Title       string
 	Type        string
 	Description sql.NullString
@@ -214,6 +278,7 @@ type UpdateTaskParams struct {
 
 func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (sql.Result, error) {
 	return q.db.ExecContext(ctx, updateTask,
+		arg.CourseID,
 		arg.Title,
 		arg.Type,
 		arg.Description,
@@ -8,6 +8,7 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"strings"
 )
 
 const countUserByEmail = `-- name: CountUserByEmail :one
@@ -22,8 +23,8 @@ func (q *Queries) CountUserByEmail(ctx context.Context, email string) (int64, er
 }
 
 const createUser = `-- name: CreateUser :execresult
-INSERT INTO users (id, name, email, password)
-VALUES (?, ?, ?, ?)
+INSERT INTO users (id, name, email, password, locale)
+VALUES (?, ?, ?, ?, ?)
 `
 
 type CreateUserParams struct {
@@ -31,6 +32,7 @@ type CreateUserParams struct {
 	Name     string
 	Email    string
 	Password string
+	Locale   string
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (sql.Result, error) {
@@ -39,54 +41,20 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (sql.Res
 		arg.Name,
 		arg.Email,
 		arg.Password,
+		arg.Locale,
 	)
 }
 
-const getAllUsers = `-- name: GetAllUsers :many
-SELECT id, name, email, profile_img, created_at, updated_at FROM users
+const deleteUser = `-- name: DeleteUser :execresult
+DELETE FROM users WHERE id = ?
 `
 
-type GetAllUsersRow struct {
-	ID         string
-	Name       string
-	Email      string
-	ProfileImg sql.NullString
-	CreatedAt  sql.NullTime
-	UpdatedAt  sql.NullTime
-}
-
-func (q *Queries) GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllUsers)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetAllUsersRow
-	for rows.Next() {
-		var i GetAllUsersRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Email,
-			&i.ProfileImg,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) DeleteUser(ctx context.Context, id string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteUser, id)
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, name, email, password, profile_img, created_at, updated_at FROM users WHERE email = ?
+SELECT id, name, email, password, profile_img, created_at, updated_at, timezone, role, disabled_at, locale FROM users WHERE email = ?
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -100,12 +68,16 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.ProfileImg,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Timezone,
+		&i.Role,
+		&i.DisabledAt,
+		&i.Locale,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, name, email, password, profile_img, created_at, updated_at FROM users
+SELECT id, name, email, password, profile_img, created_at, updated_at, timezone, role, disabled_at, locale FROM users
 WHERE id = ?
 `
 
@@ -120,6 +92,143 @@ func (q *Queries) GetUserByID(ctx context.Context, id string) (User, error) {
 		&i.ProfileImg,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Timezone,
+		&i.Role,
+		&i.DisabledAt,
+		&i.Locale,
 	)
 	return i, err
 }
+
+const getUserTimezone = `-- name: GetUserTimezone :one
+SELECT timezone FROM users WHERE id = ?
+`
+
+func (q *Queries) GetUserTimezone(ctx context.Context, id string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserTimezone, id)
+	var timezone string
+	err := row.Scan(&timezone)
+	return timezone, err
+}
+
+const setUserDisabledAt = `-- name: SetUserDisabledAt :execresult
+UPDATE users SET disabled_at = ? WHERE id = ?
+`
+
+type SetUserDisabledAtParams struct {
+	DisabledAt sql.NullTime
+	ID         string
+}
+
+func (q *Queries) SetUserDisabledAt(ctx context.Context, arg SetUserDisabledAtParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, setUserDisabledAt, arg.DisabledAt, arg.ID)
+}
+
+const updateUserEmail = `-- name: UpdateUserEmail :execresult
+UPDATE users SET email = ? WHERE id = ?
+`
+
+type UpdateUserEmailParams struct {
+	Email string
+	ID    string
+}
+
+func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateUserEmail, arg.Email, arg.ID)
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :execresult
+UPDATE users SET password = ? WHERE id = ?
+`
+
+type UpdateUserPasswordParams struct {
+	Password string
+	ID       string
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateUserPassword, arg.Password, arg.ID)
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :execresult
+UPDATE users SET name = ?, timezone = ?, locale = ? WHERE id = ?
+`
+
+type UpdateUserProfileParams struct {
+	Name     string
+	Timezone string
+	Locale   string
+	ID       string
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateUserProfile,
+		arg.Name,
+		arg.Timezone,
+		arg.Locale,
+		arg.ID,
+	)
+}
+
+const updateUserProfileImg = `-- name: UpdateUserProfileImg :execresult
+UPDATE users SET profile_img = ? WHERE id = ?
+`
+
+type UpdateUserProfileImgParams struct {
+	ProfileImg sql.NullString
+	ID         string
+}
+
+func (q *Queries) UpdateUserProfileImg(ctx context.Context, arg UpdateUserProfileImgParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateUserProfileImg, arg.ProfileImg, arg.ID)
+}
+
+const updateUserRole = `-- name: UpdateUserRole :execresult
+UPDATE users SET role = ? WHERE id = ?
+`
+
+type UpdateUserRoleParams struct {
+	Role string
+	ID   string
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateUserRole, arg.Role, arg.ID)
+}
+
+const updateUserRoleByEmails = `-- name: UpdateUserRoleByEmails :execresult
+UPDATE users SET role = ? WHERE email IN (/*SLICE:emails*/?)
+`
+
+type UpdateUserRoleByEmailsParams struct {
+	Role   string
+	Emails []string
+}
+
+func (q *Queries) UpdateUserRoleByEmails(ctx context.Context, arg UpdateUserRoleByEmailsParams) (sql.Result, error) {
+	query := updateUserRoleByEmails
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.Role)
+	if len(arg.Emails) > 0 {
+		for _, v := range arg.Emails {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:emails*/?", strings.Repeat(",?", len(arg.Emails))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:emails*/?", "NULL", 1)
+	}
+	return q.db.ExecContext(ctx, query, queryParams...)
+}
+
+const updateUserTimezone = `-- name: UpdateUserTimezone :execresult
+UPDATE users SET timezone = ? WHERE id = ?
+`
+
+type UpdateUserTimezoneParams struct {
+	Timezone string
+	ID       string
+}
+
+func (q *Queries) UpdateUserTimezone(ctx context.Context, arg UpdateUserTimezoneParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateUserTimezone, arg.Timezone, arg.ID)
+}
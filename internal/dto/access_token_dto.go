@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"courseworker/internal/db/sqlc"
+	"strings"
+	"time"
+)
+
+// AccessTokenPrefix starts every personal access token, which tells them
+// apart from JWTs without parsing and makes leaked tokens easy to search for.
+const AccessTokenPrefix = "cwpat_"
+
+// Scopes a personal access token can be granted. Session tokens are not
+// scoped and always carry the user's full access.
+const (
+	ScopeReadCourses  = "read:courses"
+	ScopeWriteCourses = "write:courses"
+	ScopeReadTasks    = "read:tasks"
+	ScopeWriteTasks   = "write:tasks"
+	ScopeReadProfile  = "read:profile"
+)
+
+type AccessTokenReq struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,oneof=read:courses write:courses read:tasks write:tasks read:profile"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
+
+type AccessTokenResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// AccessTokenCreatedResponse includes the plain token, which is only ever
+// shown once; the server keeps a hash.
+type AccessTokenCreatedResponse struct {
+	AccessTokenResponse
+	Token string `json:"token"`
+}
+
+func ToAccessTokenResponse(t *sqlc.PersonalAccessToken) AccessTokenResponse {
+	resp := AccessTokenResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Scopes:    strings.Fields(t.Scopes),
+		CreatedAt: t.CreatedAt,
+	}
+	if t.ExpiresAt.Valid {
+		resp.ExpiresAt = &t.ExpiresAt.Time
+	}
+	if t.LastUsedAt.Valid {
+		resp.LastUsedAt = &t.LastUsedAt.Time
+	}
+	return resp
+}
+
+func ToAccessTokenResponses(tokens *[]sqlc.PersonalAccessToken) []AccessTokenResponse {
+	responses := []AccessTokenResponse{}
+	for i := range *tokens {
+		responses = append(responses, ToAccessTokenResponse(&(*tokens)[i]))
+	}
+	return responses
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "courseworker/internal/db/sqlc"
+
+// UserListQuery holds the filters and cursor accepted by the user listing.
+// Only admins may filter; everyone else just gets their own account.
+type UserListQuery struct {
+	Q      string `form:"q" json:"q"`
+	Role   string `form:"role" json:"role" binding:"omitempty,oneof=user admin"`
+	Status string `form:"status" json:"status" binding:"omitempty,oneof=active disabled"`
+	Cursor string `form:"cursor" json:"cursor"`
+	Limit  int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type UserRoleReq struct {
+	Role string `json:"role" binding:"required,oneof=user admin"`
+}
+
+type UsageStatsResponse struct {
+	TotalUsers       int64 `json:"total_users"`
+	AdminUsers       int64 `json:"admin_users"`
+	DisabledUsers    int64 `json:"disabled_users"`
+	NewUsers         int64 `json:"new_users_30d"`
+	ActiveSessions   int64 `json:"active_sessions"`
+	TotalCourses     int64 `json:"total_courses"`
+	TotalTasks       int64 `json:"total_tasks"`
+	DoneTasks        int64 `json:"done_tasks"`
+	TotalAttachments int64 `json:"total_attachments"`
+	StorageBytes     int64 `json:"storage_bytes"`
+}
+
+func ToUsageStatsResponse(s *sqlc.GetUsageStatsRow) *UsageStatsResponse {
+	return &UsageStatsResponse{
+		TotalUsers:       s.TotalUsers,
+		AdminUsers:       s.AdminUsers,
+		DisabledUsers:    s.DisabledUsers,
+		NewUsers:         s.NewUsers,
+		TotalCourses:     s.TotalCourses,
+		TotalTasks:       s.TotalTasks,
+		DoneTasks:        s.DoneTasks,
+		TotalAttachments: s.TotalAttachments,
+		StorageBytes:     s.StorageBytes,
+	}
+}
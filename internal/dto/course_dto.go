@@ -45,3 +45,22 @@ type CourseCreateUpdateReq struct {
 	Name    string `json:"name"`
 	Subname string `json:"subname"`
 }
+
+// CourseExport is the document a course export produces.
+type CourseExport struct {
+	Course     CourseResponse `json:"course"`
+	Tasks      []TaskResponse `json:"tasks"`
+	ExportedAt time.Time      `json:"exported_at"`
+}
+
+// CourseExportJob asks the job workers to export a course.
+type CourseExportJob struct {
+	ExportID string `json:"export_id"`
+	UserID   string `json:"user_id"`
+	CourseID int64  `json:"course_id"`
+}
+
+type CourseExportResponse struct {
+	ID  string `json:"id"`
+	URL string `json:"url,omitempty"`
+}
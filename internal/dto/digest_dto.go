@@ -0,0 +1,48 @@
+package dto
+
+import "courseworker/internal/db/sqlc"
+
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// DigestSettingsResponse describes when the user gets the task digest.
+// SendHour is the local hour of the day and Weekday, for weekly digests,
+// counts from Sunday = 0.
+type DigestSettingsResponse struct {
+	Frequency string `json:"frequency"`
+	SendHour  int    `json:"send_hour"`
+	Weekday   int    `json:"weekday"`
+}
+
+// DefaultDigestSettings is what users who never subscribed get. Digests are
+// opt-in.
+func DefaultDigestSettings() *DigestSettingsResponse {
+	return &DigestSettingsResponse{
+		Frequency: DigestOff,
+		SendHour:  7,
+		Weekday:   1,
+	}
+}
+
+func ToDigestSettingsResponse(s *sqlc.DigestSubscription) *DigestSettingsResponse {
+	return &DigestSettingsResponse{
+		Frequency: s.Frequency,
+		SendHour:  int(s.SendHour),
+		Weekday:   int(s.Weekday),
+	}
+}
+
+type DigestSettingsReq struct {
+	Frequency string `json:"frequency" binding:"required,oneof=off daily weekly"`
+	SendHour  *int   `json:"send_hour" binding:"required,min=0,max=23"`
+	Weekday   *int   `json:"weekday" binding:"omitempty,min=0,max=6"`
+}
+
+// DigestUnsubscribeQuery is carried by the signed link in every digest.
+type DigestUnsubscribeQuery struct {
+	UserID    string `form:"uid" binding:"required"`
+	Signature string `form:"sig" binding:"required"`
+}
@@ -1,11 +1,24 @@
 package dto
 
 type Response struct {
-	Status  bool        `json:"status"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Status  bool          `json:"status"`
+	Message string        `json:"message"`
+	Data    interface{}   `json:"data,omitempty"`
+	Meta    *ResponseMeta `json:"meta,omitempty"`
+}
+
+// ResponseMeta carries pagination details of cursor paginated listings.
+// NextCursor is null on the last page.
+type ResponseMeta struct {
+	NextCursor *string `json:"next_cursor"`
 }
 
 type ResponseID struct {
 	ID any `json:"id"`
 }
+
+type PageMeta struct {
+	Page  int   `json:"page"`
+	Limit int   `json:"limit"`
+	Total int64 `json:"total"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"courseworker/internal/db/sqlc"
+	"time"
+)
+
+type IdentityResponse struct {
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToIdentityResponses(identities *[]sqlc.UserIdentity) []IdentityResponse {
+	responses := []IdentityResponse{}
+	for _, i := range *identities {
+		responses = append(responses, IdentityResponse{
+			Provider:  i.Provider,
+			Email:     i.Email,
+			CreatedAt: i.CreatedAt,
+		})
+	}
+	return responses
+}
+
+// OAuthCallbackQuery is what the provider appends to the redirect back to us.
+type OAuthCallbackQuery struct {
+	State string `form:"state"`
+	Code  string `form:"code"`
+	Error string `form:"error"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+type MFAStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+type TOTPEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse carries freshly generated recovery codes. They are
+// only ever shown this once; the server keeps hashes.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MFACodeReq struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// MFADisableReq re-authenticates the user before two-factor authentication
+// is turned off. Password is required unless the account has none.
+type MFADisableReq struct {
+	Password string `json:"password"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// MFALoginReq completes a login that stopped at the second factor. Code is
+// either a TOTP code or an unused recovery code.
+type MFALoginReq struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"courseworker/internal/db/sqlc"
+	"time"
+)
+
+// Notification types shown in the in-app inbox. Nothing raises course_shared
+// until courses can be shared.
+const (
+	NotificationDeadlineApproaching = "deadline_approaching"
+	NotificationTaskOverdue         = "task_overdue"
+	NotificationCourseShared        = "course_shared"
+	NotificationExportReady         = "export_ready"
+)
+
+// Notification is what producers publish. CourseID, TaskID and Link are
+// optional and let the frontend point at what the notification is about.
+type Notification struct {
+	UserID   string
+	Type     string
+	Title    string
+	Body     string
+	CourseID int64
+	TaskID   string
+	Link     string
+}
+
+type NotificationResponse struct {
+	ID        int64      `json:"id"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	CourseID  *int64     `json:"course_id"`
+	TaskID    *string    `json:"task_id"`
+	Link      string     `json:"link"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func ToNotificationResponse(n *sqlc.Notification) NotificationResponse {
+	resp := NotificationResponse{
+		ID:        n.ID,
+		Type:      n.Type,
+		Title:     n.Title,
+		Body:      n.Body,
+		Link:      n.Link,
+		Read:      n.ReadAt.Valid,
+		CreatedAt: n.CreatedAt,
+	}
+	if n.CourseID.Valid {
+		resp.CourseID = &n.CourseID.Int64
+	}
+	if n.TaskID.Valid {
+		resp.TaskID = &n.TaskID.String
+	}
+	if n.ReadAt.Valid {
+		resp.ReadAt = &n.ReadAt.Time
+	}
+	return resp
+}
+
+func ToNotificationResponses(notifications *[]sqlc.Notification) []NotificationResponse {
+	responses := []NotificationResponse{}
+	for i := range *notifications {
+		responses = append(responses, ToNotificationResponse(&(*notifications)[i]))
+	}
+	return responses
+}
+
+// NotificationPage is one page of the inbox, newest first, together with
+// the number of unread notifications across all pages.
+type NotificationPage struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	Page          PageMeta               `json:"page"`
+	UnreadCount   int64                  `json:"unread_count"`
+}
+
+type NotificationCountResponse struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type NotificationListQuery struct {
+	Unread bool `form:"unread" json:"unread"`
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"courseworker/internal/db/sqlc"
+	"strings"
+	"time"
+)
+
+// Reminder channels. The scheduler delivers over every channel a user has
+// enabled; in_app also gets the user overdue notices in the inbox.
+const (
+	ReminderChannelEmail = "email"
+	ReminderChannelInApp = "in_app"
+)
+
+// DefaultReminderChannels are used when a user has not picked any. They match
+// the column default of reminder_preferences.channels and the fallback in
+// GetUpcomingDeadlines.
+var DefaultReminderChannels = []string{ReminderChannelEmail, ReminderChannelInApp}
+
+// DefaultReminderOffsets apply to every task, along with the offsets of its
+// type, as long as the user has not chosen different ones.
+var DefaultReminderOffsets = []int{1440, 120}
+
+type ReminderPreferencesResponse struct {
+	Enabled        bool     `json:"enabled"`
+	DefaultOffsets []int    `json:"default_offsets"`
+	Channels       []string `json:"channels"`
+}
+
+// DefaultReminderPreferences is what users who never changed their
+// preferences get.
+func DefaultReminderPreferences() *ReminderPreferencesResponse {
+	return &ReminderPreferencesResponse{
+		Enabled:        true,
+		DefaultOffsets: DefaultReminderOffsets,
+		Channels:       DefaultReminderChannels,
+	}
+}
+
+func ToReminderPreferencesResponse(p *sqlc.ReminderPreference) *ReminderPreferencesResponse {
+	return &ReminderPreferencesResponse{
+		Enabled:        p.Enabled,
+		DefaultOffsets: ParseReminderOffsets(p.DefaultOffsets),
+		Channels:       strings.Fields(strings.ReplaceAll(p.Channels, ",", " ")),
+	}
+}
+
+// ReminderPreferencesReq replaces the user's reminder preferences. Offsets
+// are minutes before the deadline, at most 30 days.
+type ReminderPreferencesReq struct {
+	Enabled        *bool    `json:"enabled" binding:"required"`
+	DefaultOffsets []int    `json:"default_offsets" binding:"dive,min=1,max=43200"`
+	Channels       []string `json:"channels" binding:"dive,oneof=email in_app"`
+}
+
+// Reminder is a single reminder handed to a delivery channel. Deadline is
+// in the user's timezone.
+type Reminder struct {
+	TaskID     string
+	TaskTitle  string
+	CourseID   int64
+	CourseName string
+	UserID     string
+	UserName   string
+	Email      string
+	Locale     string
+	Deadline   time.Time
+	Offset     time.Duration
+}
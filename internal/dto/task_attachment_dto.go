@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"courseworker/internal/db/sqlc"
+	"time"
+)
+
+type TaskAttachmentResponse struct {
+	ID          int64     `json:"id"`
+	TaskID      string    `json:"task_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func ToTaskAttachmentResponse(a *sqlc.TaskAttachment) *TaskAttachmentResponse {
+	return &TaskAttachmentResponse{
+		ID:          a.ID,
+		TaskID:      a.TaskID,
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Checksum:    a.Checksum,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+func ToTaskAttachmentResponses(attachments *[]sqlc.TaskAttachment) []TaskAttachmentResponse {
+	responses := []TaskAttachmentResponse{}
+	for _, a := range *attachments {
+		responses = append(responses, *ToTaskAttachmentResponse(&a))
+	}
+	return responses
+}
+
+type StorageUsageResponse struct {
+	Used  int64 `json:"used"`
+	Quota int64 `json:"quota"`
+}
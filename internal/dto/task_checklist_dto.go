@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"courseworker/internal/db/sqlc"
+	"time"
+)
+
+type ChecklistItemResponse struct {
+	ID        int64     `json:"id"`
+	TaskID    string    `json:"task_id"`
+	Text      string    `json:"text"`
+	IsDone    bool      `json:"is_done"`
+	Position  int32     `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ToChecklistItemResponse(i *sqlc.TaskChecklistItem) *ChecklistItemResponse {
+	return &ChecklistItemResponse{
+		ID:        i.ID,
+		TaskID:    i.TaskID,
+		Text:      i.Text,
+		IsDone:    i.IsDone,
+		Position:  i.Position,
+		CreatedAt: i.CreatedAt,
+		UpdatedAt: i.UpdatedAt,
+	}
+}
+
+func ToChecklistItemResponses(items *[]sqlc.TaskChecklistItem) []ChecklistItemResponse {
+	responses := []ChecklistItemResponse{}
+	for _, i := range *items {
+		responses = append(responses, *ToChecklistItemResponse(&i))
+	}
+	return responses
+}
+
+type ChecklistItemCreateReq struct {
+	Text string `json:"text" binding:"required,max=255"`
+}
+
+type ChecklistItemUpdateReq struct {
+	Text   *string `json:"text" binding:"omitempty,max=255"`
+	IsDone *bool   `json:"is_done"`
+}
+
+type ChecklistReorderReq struct {
+	ItemIDs []int64 `json:"item_ids" binding:"required"`
+}
+
+// ChecklistProgress returns the percentage of finished checklist items. A task
+// without a checklist counts as either nothing or everything done.
+func ChecklistProgress(done, total int64, taskDone bool) int {
+	if total == 0 {
+		if taskDone {
+			return 100
+		}
+		return 0
+	}
+	return int(done * 100 / total)
+}
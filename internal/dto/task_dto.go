@@ -2,21 +2,28 @@ package dto
 
 import (
 	"courseworker/internal/db/sqlc"
+	"database/sql"
 	"time"
 )
 
 type TaskResponse struct {
-	ID          string    `json:"id"`
-	CourseID    int64     `json:"course_id"`
-	IsDone      bool      `json:"is_done"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Image       string    `json:"image"`
-	Type        string    `json:"type"`
-	Highlight   bool      `json:"highlight"`
-	Deadline    time.Time `json:"deadline"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string     `json:"id"`
+	CourseID    int64      `json:"course_id"`
+	IsDone      bool       `json:"is_done"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Image       string     `json:"image"`
+	Type        string     `json:"type"`
+	Highlight   bool       `json:"highlight"`
+	Deadline    time.Time  `json:"deadline"`
+	CompletedAt *time.Time `json:"completed_at"`
+	SeriesID    *string    `json:"series_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Progress    int        `json:"progress"`
+
+	Attachments []TaskAttachmentResponse `json:"attachments"`
+	Notes       []TaskNoteResponse       `json:"notes,omitempty"`
 }
 
 func ToTaskResponse(t *sqlc.Task) *TaskResponse {
@@ -24,8 +31,22 @@ func ToTaskResponse(t *sqlc.Task) *TaskResponse {
 		ID: t.ID, CourseID: t.CourseID, IsDone: t.IsDone,
 		Title: t.Title, Description: t.Description.String,
 		Image: t.Image.String, Type: t.Type, Highlight: t.Highlight,
-		Deadline: t.Deadline.Time, CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt,
+		Deadline: t.Deadline.Time, CompletedAt: nullTimePtr(t.CompletedAt),
+		SeriesID: nullStringPtr(t.SeriesID), CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// InLocation renders the task's timestamps in the given timezone.
+func (t *TaskResponse) InLocation(loc *time.Location) {
+	if !t.Deadline.IsZero() {
+		t.Deadline = t.Deadline.In(loc)
+	}
+	if t.CompletedAt != nil {
+		completedAt := t.CompletedAt.In(loc)
+		t.CompletedAt = &completedAt
 	}
+	t.CreatedAt = t.CreatedAt.In(loc)
+	t.UpdatedAt = t.UpdatedAt.In(loc)
 }
 
 func ToTaskResponses(tasks *[]sqlc.Task) []TaskResponse {
@@ -35,7 +56,8 @@ func ToTaskResponses(tasks *[]sqlc.Task) []TaskResponse {
 			ID: t.ID, CourseID: t.CourseID, IsDone: t.IsDone,
 			Title: t.Title, Description: t.Description.String,
 			Image: t.Image.String, Type: t.Type, Highlight: t.Highlight,
-			Deadline: t.Deadline.Time, CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt,
+			Deadline: t.Deadline.Time, CompletedAt: nullTimePtr(t.CompletedAt),
+			SeriesID: nullStringPtr(t.SeriesID), CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt,
 		}
 		responses = append(responses, response)
 	}
@@ -44,7 +66,65 @@ func ToTaskResponses(tasks *[]sqlc.Task) []TaskResponse {
 
 type TaskCreateReq struct {
 	Title       string `json:"title"`
-	Type        string `json:"type"`
+	Type        string `json:"type" binding:"required"`
 	Description string `json:"description"`
 	Deadline    string `json:"deadline"`
+	Recurrence  string `json:"recurrence"`
+}
+
+type TaskUpdateReq struct {
+	CourseID    int64  `json:"course_id"`
+	Title       string `json:"title" binding:"required"`
+	Type        string `json:"type" binding:"required"`
+	Description string `json:"description"`
+	Deadline    string `json:"deadline" binding:"required"`
+}
+
+type TaskPatchReq struct {
+	CourseID    *int64  `json:"course_id"`
+	Title       *string `json:"title"`
+	Type        *string `json:"type"`
+	Description *string `json:"description"`
+	Deadline    *string `json:"deadline"`
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+const (
+	TaskScopeThis      = "this"
+	TaskScopeFollowing = "following"
+	TaskScopeAll       = "all"
+)
+
+// TaskScopeQuery selects which occurrences of a recurring task an edit or
+// delete applies to. It defaults to the addressed occurrence only.
+type TaskScopeQuery struct {
+	Scope string `form:"scope" json:"scope" binding:"omitempty,oneof=this following all"`
+}
+
+// TaskListQuery holds the filters, sort order and cursor accepted by the task
+// listing endpoints.
+type TaskListQuery struct {
+	Status       string `form:"status" json:"status" binding:"omitempty,oneof=open done overdue"`
+	Type         string `form:"type" json:"type"`
+	Highlight    *bool  `form:"highlight" json:"highlight"`
+	Q            string `form:"q" json:"q"`
+	DeadlineFrom string `form:"deadline_from" json:"deadline_from"`
+	DeadlineTo   string `form:"deadline_to" json:"deadline_to"`
+	Sort         string `form:"sort" json:"sort" binding:"omitempty,oneof=deadline created_at title"`
+	Order        string `form:"order" json:"order" binding:"omitempty,oneof=asc desc"`
+	Cursor       string `form:"cursor" json:"cursor"`
+	Limit        int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=100"`
 }
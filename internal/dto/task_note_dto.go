@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"courseworker/internal/db/sqlc"
+	"time"
+)
+
+type TaskNoteResponse struct {
+	ID        int64     `json:"id"`
+	TaskID    string    `json:"task_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ToTaskNoteResponse(n *sqlc.TaskNote) *TaskNoteResponse {
+	return &TaskNoteResponse{
+		ID:        n.ID,
+		TaskID:    n.TaskID,
+		Text:      n.Text,
+		CreatedAt: n.CreatedAt,
+		UpdatedAt: n.UpdatedAt,
+	}
+}
+
+func ToTaskNoteResponses(notes *[]sqlc.TaskNote) []TaskNoteResponse {
+	responses := []TaskNoteResponse{}
+	for _, n := range *notes {
+		response := TaskNoteResponse{
+			ID:        n.ID,
+			TaskID:    n.TaskID,
+			Text:      n.Text,
+			CreatedAt: n.CreatedAt,
+			UpdatedAt: n.UpdatedAt,
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+type TaskNotePage struct {
+	Notes []TaskNoteResponse `json:"notes"`
+	Page  PageMeta           `json:"page"`
+}
+
+type TaskNoteCreateUpdateReq struct {
+	Text string `json:"text" binding:"required"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"courseworker/internal/db/sqlc"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TaskTypeResponse struct {
+	ID              int64     `json:"id"`
+	Name            string    `json:"name"`
+	Color           string    `json:"color"`
+	ReminderOffsets []int     `json:"reminder_offsets"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func ToTaskTypeResponse(t *sqlc.TaskType) *TaskTypeResponse {
+	return &TaskTypeResponse{
+		ID:              t.ID,
+		Name:            t.Name,
+		Color:           t.Color,
+		ReminderOffsets: ParseReminderOffsets(t.ReminderOffsets),
+		CreatedAt:       t.CreatedAt,
+		UpdatedAt:       t.UpdatedAt,
+	}
+}
+
+func ToTaskTypeResponses(types *[]sqlc.TaskType) []TaskTypeResponse {
+	responses := []TaskTypeResponse{}
+	for _, t := range *types {
+		responses = append(responses, *ToTaskTypeResponse(&t))
+	}
+	return responses
+}
+
+// TaskTypeCreateUpdateReq describes a task type. ReminderOffsets are extra
+// reminder lead times in minutes before the deadline, sent on top of the
+// user's default offsets.
+type TaskTypeCreateUpdateReq struct {
+	Name            string `json:"name" binding:"required,max=20"`
+	Color           string `json:"color" binding:"required,hexcolor"`
+	ReminderOffsets []int  `json:"reminder_offsets" binding:"dive,min=1"`
+}
+
+// ParseReminderOffsets decodes the comma separated minute offsets stored in
+// task_types.reminder_offsets.
+func ParseReminderOffsets(s string) []int {
+	offsets := []int{}
+	for _, part := range strings.Split(s, ",") {
+		if v, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			offsets = append(offsets, v)
+		}
+	}
+	return offsets
+}
+
+func FormatReminderOffsets(offsets []int) string {
+	parts := make([]string, len(offsets))
+	for i, v := range offsets {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ",")
+}
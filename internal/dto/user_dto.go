@@ -5,15 +5,27 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
 )
 
 type UserResponse struct {
-	ID         string    `json:"id"`
-	Name       string    `json:"name"`
-	Email      string    `json:"email"`
-	ProfileImg string    `json:"profile_img"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	ProfileImg string     `json:"profile_img"`
+	Timezone   string     `json:"timezone"`
+	Locale     string     `json:"locale"`
+	Role       string     `json:"role"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	Avatars map[string]string `json:"avatars,omitempty"`
 }
 
 func ToUserResponse(u *sqlc.User) *UserResponse {
@@ -22,19 +34,27 @@ func ToUserResponse(u *sqlc.User) *UserResponse {
 		Name:       u.Name,
 		Email:      u.Email,
 		ProfileImg: u.ProfileImg.String,
+		Timezone:   u.Timezone,
+		Locale:     u.Locale,
+		Role:       u.Role,
+		DisabledAt: nullTimePtr(u.DisabledAt),
 		CreatedAt:  u.CreatedAt.Time,
 		UpdatedAt:  u.UpdatedAt.Time,
 	}
 }
 
-func ToUserResponses(users *[]sqlc.GetAllUsersRow) []UserResponse {
-	var responses []UserResponse
+func ToUserResponses(users *[]sqlc.User) []UserResponse {
+	responses := []UserResponse{}
 	for _, u := range *users {
 		response := UserResponse{
 			ID:         u.ID,
 			Name:       u.Name,
 			Email:      u.Email,
 			ProfileImg: u.ProfileImg.String,
+			Timezone:   u.Timezone,
+			Locale:     u.Locale,
+			Role:       u.Role,
+			DisabledAt: nullTimePtr(u.DisabledAt),
 			CreatedAt:  u.CreatedAt.Time,
 			UpdatedAt:  u.UpdatedAt.Time,
 		}
@@ -45,28 +65,47 @@ func ToUserResponses(users *[]sqlc.GetAllUsersRow) []UserResponse {
 
 type UserClaims struct {
 	ID          string `json:"id" binding:"required"`
+	SessionID   string `json:"sid,omitempty"`
+	Role        string `json:"role,omitempty"`
+	Version     int64  `json:"ver"`
 	ExpDuration int64  `json:"exp_duration"`
 	jwt.RegisteredClaims
+
+	// TokenID and Scopes are only set when the request was authenticated
+	// with a personal access token instead of a session.
+	TokenID string   `json:"-"`
+	Scopes  []string `json:"-"`
+}
+
+// HasScope reports whether the token may be used for scope. Session tokens
+// are never restricted.
+func (c *UserClaims) HasScope(scope string) bool {
+	if c.TokenID == "" {
+		return true
+	}
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
 }
 
-func NewUserClaims(ID string, exp time.Duration) UserClaims {
+func NewUserClaims(ID, sessionID, role string, version int64, exp time.Duration) UserClaims {
 	return UserClaims{
 		ID:          ID,
+		SessionID:   sessionID,
+		Role:        role,
+		Version:     version,
 		ExpDuration: int64(exp.Seconds()),
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(exp)),
 		},
 	}
 }
 
-type AuthenticatedUser struct {
-	ID            string `json:"localId"`
-	Email         string `json:"email"`
-	EmailVerified bool   `json:"emailVerified"`
-	Name          string `json:"name"`
-	Photo         string `json:"photoUrl"`
-}
-
 type RegisterUserReq struct {
 	Name            string `json:"name"`
 	Email           string `json:"email" binding:"required"`
@@ -96,6 +135,7 @@ type CreateUserParams struct {
 	Name     string
 	Email    string
 	HashedPw string
+	Locale   string
 }
 
 type LoginUserReq struct {
@@ -103,8 +143,51 @@ type LoginUserReq struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type ForgotPasswordReq struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type ResetPasswordReq struct {
+	Token           string `json:"token" binding:"required"`
+	Password        string `json:"password" binding:"required"`
+	ConfirmPassword string `json:"confirm_password" binding:"required"`
+}
+
+type UserTimezoneReq struct {
+	Timezone string `json:"timezone" binding:"required"`
+}
+
+type ProfileUpdateReq struct {
+	Name     *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Timezone *string `json:"timezone"`
+	Locale   *string `json:"locale"`
+}
+
+type EmailChangeReq struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type PasswordChangeReq struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+	ConfirmPassword string `json:"confirm_password" binding:"required"`
+}
+
+// AccountDeleteReq re-authenticates the user before the account is deleted.
+// Password is required unless the account has none.
+type AccountDeleteReq struct {
+	Password string `json:"password"`
+}
+
+// TokenResp is the result of a login. When the account has two-factor
+// authentication enabled, Token is empty and MFAToken must be exchanged at
+// POST /login/mfa together with a code.
 type TokenResp struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
 }
 
 func ToTokenResp(t string) *TokenResp {
@@ -112,3 +195,7 @@ func ToTokenResp(t string) *TokenResp {
 		Token: t,
 	}
 }
+
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
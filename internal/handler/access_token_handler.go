@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"courseworker/internal/dto"
+	"courseworker/internal/service"
+	"courseworker/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccessTokenHandler struct {
+	serv service.AccessTokenService
+}
+
+func NewAccessTokenHandler(s service.AccessTokenService) *AccessTokenHandler {
+	return &AccessTokenHandler{s}
+}
+
+func (h *AccessTokenHandler) GetTokens(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	resp, err := h.serv.GetTokens(c, claims.ID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, accessTokensFetchSuccess, resp)
+}
+
+func (h *AccessTokenHandler) CreateToken(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var req dto.AccessTokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.CreateToken(c, claims.ID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, accessTokenCreateSuccess, resp)
+}
+
+func (h *AccessTokenHandler) DeleteToken(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	if err := h.serv.DeleteToken(c, claims.ID, c.Param("tokenId")); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, accessTokenDeleteSuccess, nil)
+}
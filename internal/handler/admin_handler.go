@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"courseworker/internal/dto"
+	"courseworker/internal/service"
+	"courseworker/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	serv service.AdminService
+}
+
+func NewAdminHandler(s service.AdminService) *AdminHandler {
+	return &AdminHandler{s}
+}
+
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	resp, err := h.serv.DisableUser(c, claims.ID, c.Param("userId"))
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, userDisableSuccess, resp)
+}
+
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	resp, err := h.serv.EnableUser(c, c.Param("userId"))
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, userEnableSuccess, resp)
+}
+
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var req dto.UserRoleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.UpdateUserRole(c, claims.ID, c.Param("userId"), req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, userRoleUpdateSuccess, resp)
+}
+
+func (h *AdminHandler) ForceLogout(c *gin.Context) {
+	if err := h.serv.ForceLogout(c, c.Param("userId")); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, userForceLogoutSuccess, nil)
+}
+
+func (h *AdminHandler) GetUsageStats(c *gin.Context) {
+	resp, err := h.serv.GetUsageStats(c)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, usageStatsFetchSuccess, resp)
+}
@@ -7,15 +7,98 @@ const (
 	courseUpdateSuccess = "Course successfully updated."
 	courseDeleteSuccess = "Course successfully deleted."
 
+	courseExportSuccess      = "Course export started. You will be notified once it is ready."
+	courseExportFetchSuccess = "Course export successfully retrieved."
+
 	taskFetchSuccess  = "Task successfully retrieved."
 	tasksFetchSuccess = "Tasks successfully retrieved."
 	taskCreateSuccess = "Task successfully created."
 	taskUpdateSuccess = "Task successfully updated."
 	taskDeleteSuccess = "Task successfully deleted."
 
+	taskImageUploadSuccess = "Task image successfully uploaded."
+	taskImageRemoveSuccess = "Task image successfully removed."
+
+	taskTypesFetchSuccess = "Task types successfully retrieved."
+	taskTypeCreateSuccess = "Task type successfully created."
+	taskTypeUpdateSuccess = "Task type successfully updated."
+	taskTypeDeleteSuccess = "Task type successfully deleted."
+
+	noteFetchSuccess  = "Note successfully retrieved."
+	notesFetchSuccess = "Notes successfully retrieved."
+	noteCreateSuccess = "Note successfully created."
+	noteUpdateSuccess = "Note successfully updated."
+	noteDeleteSuccess = "Note successfully deleted."
+
+	checklistFetchSuccess      = "Checklist successfully retrieved."
+	checklistItemCreateSuccess = "Checklist item successfully created."
+	checklistItemUpdateSuccess = "Checklist item successfully updated."
+	checklistItemDeleteSuccess = "Checklist item successfully deleted."
+	checklistReorderSuccess    = "Checklist successfully reordered."
+
+	attachmentsFetchSuccess  = "Attachments successfully retrieved."
+	attachmentUploadSuccess  = "Attachment successfully uploaded."
+	attachmentDeleteSuccess  = "Attachment successfully deleted."
+	storageUsageFetchSuccess = "Storage usage successfully retrieved."
+
 	userFetchSuccess    = "User successfully retrieved."
 	usersFetchSuccess   = "Users successfully retrieved."
 	userCreateSuccess   = "User successfully created."
 	userRegisterSuccess = "Your account has been registered. If you don't see a confirmation email, try again later."
 	userLoginSuccess    = "User successfully logged in."
+
+	userTimezoneUpdateSuccess = "Timezone successfully updated."
+
+	providersFetchSuccess  = "Login providers successfully retrieved."
+	identitiesFetchSuccess = "Linked accounts successfully retrieved."
+	identityLinkSuccess    = "Account successfully linked."
+	identityUnlinkSuccess  = "Account successfully unlinked."
+
+	userDisableSuccess     = "User successfully disabled."
+	userEnableSuccess      = "User successfully enabled."
+	userRoleUpdateSuccess  = "User role successfully updated."
+	userForceLogoutSuccess = "User successfully logged out of all sessions."
+	usageStatsFetchSuccess = "Usage stats successfully retrieved."
+
+	mfaRequired           = "Two-factor authentication required. Submit a code to finish logging in."
+	mfaStatusFetchSuccess = "Two-factor status successfully retrieved."
+	totpEnrollSuccess     = "Add the key to your authenticator app, then verify a code to finish enrollment."
+	totpEnableSuccess     = "Two-factor authentication successfully enabled. Store your recovery codes safely."
+	totpDisableSuccess    = "Two-factor authentication successfully disabled."
+	recoveryCodesSuccess  = "Recovery codes successfully regenerated."
+
+	profileFetchSuccess       = "Profile successfully retrieved."
+	profileUpdateSuccess      = "Profile successfully updated."
+	avatarUploadSuccess       = "Avatar successfully uploaded."
+	avatarRemoveSuccess       = "Avatar successfully removed."
+	emailChangeRequestSuccess = "A confirmation link has been sent to your new email address."
+	emailChangeSuccess        = "Email successfully changed."
+	passwordChangeSuccess     = "Password successfully changed. Other sessions have been logged out."
+	accountDeleteSuccess      = "Account successfully deleted."
+
+	passwordForgotSuccess = "If an account exists for this email, a password reset link has been sent."
+	passwordResetSuccess  = "Password successfully reset. Please log in again."
+
+	reminderPrefsFetchSuccess  = "Reminder preferences successfully retrieved."
+	reminderPrefsUpdateSuccess = "Reminder preferences successfully updated."
+
+	digestSettingsFetchSuccess  = "Digest settings successfully retrieved."
+	digestSettingsUpdateSuccess = "Digest settings successfully updated."
+	digestUnsubscribeSuccess    = "You have been unsubscribed from task digests."
+
+	notificationsFetchSuccess     = "Notifications successfully retrieved."
+	notificationCountFetchSuccess = "Unread notification count successfully retrieved."
+	notificationReadSuccess       = "Notification successfully marked as read."
+	notificationsReadSuccess      = "All notifications successfully marked as read."
+	notificationDeleteSuccess     = "Notification successfully deleted."
+
+	accessTokensFetchSuccess = "Access tokens successfully retrieved."
+	accessTokenCreateSuccess = "Access token successfully created. Copy it now, it will not be shown again."
+	accessTokenDeleteSuccess = "Access token successfully deleted."
+
+	tokenRefreshSuccess  = "Token successfully refreshed."
+	sessionsFetchSuccess = "Sessions successfully retrieved."
+	sessionRevokeSuccess = "Session successfully revoked."
+	userLogoutSuccess    = "User successfully logged out."
+	userLogoutAllSuccess = "User successfully logged out of all sessions."
 )
@@ -120,3 +120,47 @@ func (h *CourseHandler) DeleteCourse(c *gin.Context) {
 	}
 	response.Success(c, http.StatusOK, courseDeleteSuccess, nil)
 }
+
+func (h *CourseHandler) ExportCourse(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	courseID, err := strconv.Atoi(c.Param("courseId"))
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/ExportCourse"),
+			_error.InvalidRequest,
+			_error.Title("Failed to convert id from params"),
+		))
+		return
+	}
+
+	resp, err := h.serv.ExportCourse(c, claims.ID, int64(courseID))
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusAccepted, courseExportSuccess, resp)
+}
+
+func (h *CourseHandler) GetExport(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	courseID, err := strconv.Atoi(c.Param("courseId"))
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/GetExport"),
+			_error.InvalidRequest,
+			_error.Title("Failed to convert id from params"),
+		))
+		return
+	}
+
+	resp, err := h.serv.GetExport(c, claims.ID, int64(courseID), c.Param("exportId"))
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, courseExportFetchSuccess, resp)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"courseworker/internal/dto"
+	"courseworker/internal/service"
+	"courseworker/pkg/response"
+	"embed"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+//go:embed templates/digest_unsubscribe.html.tmpl
+var digestTemplateFS embed.FS
+
+var digestUnsubscribePage = template.Must(template.ParseFS(digestTemplateFS, "templates/digest_unsubscribe.html.tmpl"))
+
+type digestUnsubscribePageData struct {
+	Done    bool
+	Message string
+	Action  string
+}
+
+type DigestHandler struct {
+	serv service.DigestService
+}
+
+func NewDigestHandler(s service.DigestService) *DigestHandler {
+	return &DigestHandler{s}
+}
+
+func (h *DigestHandler) GetSettings(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	resp, err := h.serv.GetSettings(c, claims.ID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, digestSettingsFetchSuccess, resp)
+}
+
+func (h *DigestHandler) UpdateSettings(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var req dto.DigestSettingsReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.UpdateSettings(c, claims.ID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, digestSettingsUpdateSuccess, resp)
+}
+
+// UnsubscribePage is where the link in the email leads. It only asks for
+// confirmation, so link scanners and prefetching mail clients that follow it
+// do not unsubscribe anyone.
+func (h *DigestHandler) UnsubscribePage(c *gin.Context) {
+	var query dto.DigestUnsubscribeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.HttpBindingError(c, err, query)
+		return
+	}
+
+	if err := h.serv.VerifyUnsubscribe(c, query); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	c.Render(http.StatusOK, render.HTML{
+		Template: digestUnsubscribePage,
+		Data:     digestUnsubscribePageData{Action: c.Request.URL.RequestURI()},
+	})
+}
+
+// Unsubscribe serves both the confirmation form and the one-click
+// unsubscribe mail clients send. Browsers get a page back, anything else
+// JSON.
+func (h *DigestHandler) Unsubscribe(c *gin.Context) {
+	var query dto.DigestUnsubscribeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.HttpBindingError(c, err, query)
+		return
+	}
+
+	if err := h.serv.Unsubscribe(c, query); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		c.Render(http.StatusOK, render.HTML{
+			Template: digestUnsubscribePage,
+			Data:     digestUnsubscribePageData{Done: true, Message: digestUnsubscribeSuccess},
+		})
+		return
+	}
+	response.Success(c, http.StatusOK, digestUnsubscribeSuccess, nil)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	_error "courseworker/pkg/error"
+	"courseworker/pkg/response"
+	"courseworker/pkg/storage"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FileHandler serves blobs of the local storage backend through the signed
+// links produced by storage.Local.SignedURL.
+type FileHandler struct {
+	store *storage.Local
+}
+
+func NewFileHandler(s *storage.Local) *FileHandler {
+	return &FileHandler{s}
+}
+
+func (h *FileHandler) ServeFile(c *gin.Context) {
+	const op _error.Op = "hand/ServeFile"
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	if err := h.store.Verify(key, c.Query("expires"), c.Query("signature")); err != nil {
+		response.HttpError(c, _error.E(op, _error.Forbidden, _error.Title("Failed to get file"), err))
+		return
+	}
+
+	obj, err := h.store.Open(c, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			response.HttpError(c, _error.E(op, _error.NotExist, _error.Title("Failed to get file"), "file not found"))
+			return
+		}
+		response.HttpError(c, _error.E(op, _error.Internal, _error.Title("Failed to get file"), err))
+		return
+	}
+	defer obj.Body.Close()
+
+	c.DataFromReader(http.StatusOK, obj.Size, obj.ContentType, obj.Body, nil)
+}
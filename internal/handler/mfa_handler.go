@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"courseworker/internal/dto"
+	"courseworker/internal/service"
+	"courseworker/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MFAHandler struct {
+	serv service.MFAService
+}
+
+func NewMFAHandler(s service.MFAService) *MFAHandler {
+	return &MFAHandler{s}
+}
+
+func (h *MFAHandler) VerifyLogin(c *gin.Context) {
+	var req dto.MFALoginReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.VerifyLogin(c, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, userLoginSuccess, resp)
+}
+
+func (h *MFAHandler) GetStatus(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	resp, err := h.serv.GetStatus(c, claims.ID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, mfaStatusFetchSuccess, resp)
+}
+
+func (h *MFAHandler) EnrollTOTP(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	resp, err := h.serv.EnrollTOTP(c, claims.ID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, totpEnrollSuccess, resp)
+}
+
+func (h *MFAHandler) EnableTOTP(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var req dto.MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.EnableTOTP(c, claims.ID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, totpEnableSuccess, resp)
+}
+
+func (h *MFAHandler) DisableTOTP(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var req dto.MFADisableReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	if err := h.serv.DisableTOTP(c, claims.ID, req); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, totpDisableSuccess, nil)
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var req dto.MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.RegenerateRecoveryCodes(c, claims.ID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, recoveryCodesSuccess, resp)
+}
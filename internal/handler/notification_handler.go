@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"courseworker/internal/dto"
+	"courseworker/internal/service"
+	_error "courseworker/pkg/error"
+	"courseworker/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	serv service.NotificationService
+}
+
+func NewNotificationHandler(s service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{s}
+}
+
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var query dto.NotificationListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.HttpBindingError(c, err, query)
+		return
+	}
+
+	page, limit, err := parsePage(c)
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/GetNotifications"), _error.InvalidRequest,
+			_error.Title("Failed to get notifications"), err,
+		))
+		return
+	}
+
+	resp, err := h.serv.GetNotifications(c, claims.ID, query, page, limit)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, notificationsFetchSuccess, resp)
+}
+
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	resp, err := h.serv.GetUnreadCount(c, claims.ID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, notificationCountFetchSuccess, resp)
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	notificationID, err := paramID(c, "notificationId")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/MarkNotificationRead"), _error.InvalidRequest,
+			_error.Title("Failed to mark notification as read"), err,
+		))
+		return
+	}
+
+	if err := h.serv.MarkRead(c, claims.ID, notificationID); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, notificationReadSuccess, nil)
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	if err := h.serv.MarkAllRead(c, claims.ID); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, notificationsReadSuccess, nil)
+}
+
+func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	notificationID, err := paramID(c, "notificationId")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/DeleteNotification"), _error.InvalidRequest,
+			_error.Title("Failed to delete notification"), err,
+		))
+		return
+	}
+
+	if err := h.serv.DeleteNotification(c, claims.ID, notificationID); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, notificationDeleteSuccess, nil)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"courseworker/internal/dto"
+	"courseworker/internal/service"
+	"courseworker/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OAuthHandler struct {
+	serv service.OAuthService
+}
+
+func NewOAuthHandler(s service.OAuthService) *OAuthHandler {
+	return &OAuthHandler{s}
+}
+
+func (h *OAuthHandler) GetProviders(c *gin.Context) {
+	response.Success(c, http.StatusOK, providersFetchSuccess, h.serv.GetProviders(c))
+}
+
+func (h *OAuthHandler) Login(c *gin.Context) {
+	h.login(c, c.Param("provider"))
+}
+
+// LoginWithGoogle is kept for clients built against the original Google-only
+// login route.
+func (h *OAuthHandler) LoginWithGoogle(c *gin.Context) {
+	h.login(c, "google")
+}
+
+func (h *OAuthHandler) login(c *gin.Context, provider string) {
+	authURL, err := h.serv.AuthURL(c, provider, "")
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+
+	// in string
+	c.String(http.StatusOK, authURL)
+}
+
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	var query dto.OAuthCallbackQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.HttpBindingError(c, err, query)
+		return
+	}
+
+	resp, err := h.serv.Callback(c, c.Param("provider"), query)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	if resp == nil {
+		response.Success(c, http.StatusOK, identityLinkSuccess, nil)
+		return
+	}
+	if resp.MFARequired {
+		response.Success(c, http.StatusOK, mfaRequired, resp)
+		return
+	}
+	response.Success(c, http.StatusOK, userLoginSuccess, resp)
+}
+
+func (h *OAuthHandler) LinkIdentity(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	authURL, err := h.serv.AuthURL(c, c.Param("provider"), claims.ID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+
+	// in string
+	c.String(http.StatusOK, authURL)
+}
+
+func (h *OAuthHandler) GetIdentities(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	resp, err := h.serv.GetIdentities(c, claims.ID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, identitiesFetchSuccess, resp)
+}
+
+func (h *OAuthHandler) UnlinkIdentity(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	if err := h.serv.UnlinkIdentity(c, claims.ID, c.Param("provider")); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, identityUnlinkSuccess, nil)
+}
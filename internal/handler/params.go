@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// parsePage reads the `page` and `limit` query parameters, falling back to
+// the first page of defaultPageLimit items.
+func parsePage(c *gin.Context) (page, limit int, err error) {
+	page, limit = 1, defaultPageLimit
+
+	if v := c.Query("page"); v != "" {
+		page, err = strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return 0, 0, errors.New("page must be a positive number")
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return 0, 0, errors.New("limit must be a number between 1 and 100")
+		}
+	}
+	return page, limit, nil
+}
+
+// hasInclude reports whether the comma separated `include` query parameter
+// lists the given relation.
+func hasInclude(c *gin.Context, relation string) bool {
+	for _, v := range strings.Split(c.Query("include"), ",") {
+		if strings.TrimSpace(v) == relation {
+			return true
+		}
+	}
+	return false
+}
+
+// paramID parses the named path parameter as a numeric id.
+func paramID(c *gin.Context, name string) (int64, error) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", name)
+	}
+	return id, nil
+}
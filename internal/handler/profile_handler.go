@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"courseworker/internal/dto"
+	"courseworker/internal/service"
+	_error "courseworker/pkg/error"
+	"courseworker/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileHandler struct {
+	serv service.ProfileService
+}
+
+func NewProfileHandler(s service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{s}
+}
+
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	resp, err := h.serv.GetProfile(c, claims.ID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, profileFetchSuccess, resp)
+}
+
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var req dto.ProfileUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.UpdateProfile(c, claims.ID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, profileUpdateSuccess, resp)
+}
+
+func (h *ProfileHandler) UploadAvatar(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/UploadAvatar"), _error.InvalidRequest,
+			_error.Title("Failed to upload avatar"), "avatar is required",
+		))
+		return
+	}
+
+	resp, err := h.serv.UploadAvatar(c, claims.ID, file)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, avatarUploadSuccess, resp)
+}
+
+func (h *ProfileHandler) RemoveAvatar(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	resp, err := h.serv.RemoveAvatar(c, claims.ID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, avatarRemoveSuccess, resp)
+}
+
+func (h *ProfileHandler) RequestEmailChange(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var req dto.EmailChangeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.RequestEmailChange(c, claims.ID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, emailChangeRequestSuccess, resp)
+}
+
+func (h *ProfileHandler) ConfirmEmailChange(c *gin.Context) {
+	resp, err := h.serv.ConfirmEmailChange(c, c.Query("token"))
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, emailChangeSuccess, resp)
+}
+
+func (h *ProfileHandler) ChangePassword(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var req dto.PasswordChangeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.ChangePassword(c, claims.ID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, passwordChangeSuccess, resp)
+}
+
+func (h *ProfileHandler) DeleteAccount(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var req dto.AccountDeleteReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	if err := h.serv.DeleteAccount(c, claims.ID, req); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, accountDeleteSuccess, nil)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"courseworker/internal/dto"
+	"courseworker/internal/service"
+	"courseworker/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReminderHandler struct {
+	serv service.ReminderService
+}
+
+func NewReminderHandler(s service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{s}
+}
+
+func (h *ReminderHandler) GetPreferences(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	resp, err := h.serv.GetPreferences(c, claims.ID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, reminderPrefsFetchSuccess, resp)
+}
+
+func (h *ReminderHandler) UpdatePreferences(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var req dto.ReminderPreferencesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.UpdatePreferences(c, claims.ID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, reminderPrefsUpdateSuccess, resp)
+}
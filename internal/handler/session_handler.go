@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"courseworker/internal/dto"
+	"courseworker/internal/service"
+	"courseworker/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	serv service.SessionService
+}
+
+func NewSessionHandler(s service.SessionService) *SessionHandler {
+	return &SessionHandler{s}
+}
+
+func (h *SessionHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.Refresh(c, req.RefreshToken)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, tokenRefreshSuccess, resp)
+}
+
+func (h *SessionHandler) GetSessions(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	resp, err := h.serv.GetSessions(c, claims.ID, claims.SessionID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, sessionsFetchSuccess, resp)
+}
+
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	if err := h.serv.RevokeSession(c, claims.ID, c.Param("sessionId")); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, sessionRevokeSuccess, nil)
+}
+
+func (h *SessionHandler) Logout(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	if err := h.serv.Logout(c, claims); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, userLogoutSuccess, nil)
+}
+
+func (h *SessionHandler) LogoutAll(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	if err := h.serv.RevokeAll(c, claims.ID); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, userLogoutAllSuccess, nil)
+}
@@ -1,58 +1,271 @@
 package handler
 
 import (
+	"context"
 	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
 	"courseworker/internal/repository"
 	"courseworker/internal/service"
 	"courseworker/middleware"
+	"courseworker/pkg/jobs"
+	"courseworker/pkg/mailer"
+	"courseworker/pkg/oauth"
+	"courseworker/pkg/ratelimit"
+	"courseworker/pkg/storage"
 	"database/sql"
+	"log"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 )
 
-func route(r *gin.Engine, uh *UserHandler, ch *CourseHandler, th *TaskHandler) {
-	r.GET("/users", uh.GetUsers)
-	r.GET("/users/:userId", uh.GetUserByID)
-	r.GET("/auth/google/login-w-google", uh.LoginWithGoogle)
-	r.GET("/auth/google/callback", uh.GetGoogleDetails)
-	r.POST("/register", uh.RegisterUser)
-	r.GET("/account-confirm", uh.CreateConfirmedUser)
-	r.POST("/login", uh.LoginUser)
-
-	r.GET("/courses", middleware.ValidateToken(), ch.GetCourses)
-	r.GET("/courses/:courseId", middleware.ValidateToken(), ch.GetCourseByID)
-	r.POST("/courses", middleware.ValidateToken(), ch.CreateCourse)
-	r.PUT("/courses/:courseId", middleware.ValidateToken(), ch.UpdateCourse)
-	r.DELETE("/courses/:courseId", middleware.ValidateToken(), ch.DeleteCourse)
-
-	r.GET("/courses/tasks", middleware.ValidateToken(), th.GetAllTasks)
-	r.GET("/courses/:courseId/tasks", middleware.ValidateToken(), th.GetTasksByCourse)
-	r.GET("/courses/:courseId/tasks/:taskId", middleware.ValidateToken(), th.GetTaskByID)
-	r.POST("/courses/:courseId/tasks", middleware.ValidateToken(), th.CreateTask)
-	r.PUT("/courses/:courseId/tasks/:taskId/highlight", middleware.ValidateToken(), th.SwitchTaskHighlight)
-	r.DELETE("/courses/:courseId/tasks/:taskId", middleware.ValidateToken(), th.DeleteTask)
+func route(r *gin.Engine, rd *redis.Client, uh *UserHandler, ch *CourseHandler, th *TaskHandler, nh *TaskNoteHandler, ah *TaskAttachmentHandler, tth *TaskTypeHandler, clh *TaskChecklistHandler, sh *SessionHandler, ph *ProfileHandler, adh *AdminHandler, oh *OAuthHandler, mh *MFAHandler, tkh *AccessTokenHandler, rh *ReminderHandler, dh *DigestHandler, nth *NotificationHandler) {
+	// auth only accepts sessions; the scoped variants also let personal
+	// access tokens holding that scope through.
+	auth := middleware.ValidateToken(rd, tkh.serv)
+	readProfile := middleware.ValidateToken(rd, tkh.serv, dto.ScopeReadProfile)
+	readCourses := middleware.ValidateToken(rd, tkh.serv, dto.ScopeReadCourses)
+	writeCourses := middleware.ValidateToken(rd, tkh.serv, dto.ScopeWriteCourses)
+	readTasks := middleware.ValidateToken(rd, tkh.serv, dto.ScopeReadTasks)
+	writeTasks := middleware.ValidateToken(rd, tkh.serv, dto.ScopeWriteTasks)
+	admin := middleware.RequireRole(dto.RoleAdmin)
+
+	// Per-IP limits on the unauthenticated auth endpoints. Each can be
+	// overridden with RATE_LIMIT_<NAME>, e.g. RATE_LIMIT_LOGIN=10/1m.
+	limit := func(name string, n int, window time.Duration) gin.HandlerFunc {
+		return middleware.RateLimit(rd, ratelimit.FromEnv(ratelimit.Rule{Name: name, Limit: n, Window: window}))
+	}
+	loginLimit := limit("login", 10, time.Minute)
+	mfaLimit := limit("login-mfa", 10, time.Minute)
+	registerLimit := limit("register", 5, 10*time.Minute)
+	confirmLimit := limit("account-confirm", 10, 10*time.Minute)
+	passwordLimit := limit("password", 5, 10*time.Minute)
+
+	r.GET("/users", readProfile, uh.GetUsers)
+	r.GET("/users/:userId", readProfile, uh.GetUserByID)
+	r.GET("/auth/providers", oh.GetProviders)
+	r.GET("/auth/:provider/login", oh.Login)
+	r.GET("/auth/:provider/callback", oh.Callback)
+	r.GET("/auth/google/login-w-google", oh.LoginWithGoogle)
+	r.POST("/register", registerLimit, uh.RegisterUser)
+	r.GET("/account-confirm", confirmLimit, uh.CreateConfirmedUser)
+	r.POST("/login", loginLimit, uh.LoginUser)
+	r.POST("/login/mfa", mfaLimit, mh.VerifyLogin)
+	r.POST("/password/forgot", passwordLimit, uh.ForgotPassword)
+	r.POST("/password/reset", passwordLimit, uh.ResetPassword)
+	r.PUT("/me/timezone", auth, uh.UpdateTimezone)
+
+	r.GET("/me", readProfile, ph.GetProfile)
+	r.PATCH("/me", auth, ph.UpdateProfile)
+	r.PUT("/me/avatar", auth, ph.UploadAvatar)
+	r.DELETE("/me/avatar", auth, ph.RemoveAvatar)
+	r.POST("/me/email", auth, ph.RequestEmailChange)
+	r.GET("/me/email/confirm", ph.ConfirmEmailChange)
+	r.PUT("/me/password", auth, ph.ChangePassword)
+	r.DELETE("/me", auth, ph.DeleteAccount)
+
+	r.GET("/me/mfa", auth, mh.GetStatus)
+	r.POST("/me/mfa/totp", auth, mh.EnrollTOTP)
+	r.POST("/me/mfa/totp/verify", auth, mh.EnableTOTP)
+	r.POST("/me/mfa/totp/disable", auth, mh.DisableTOTP)
+	r.POST("/me/mfa/recovery-codes", auth, mh.RegenerateRecoveryCodes)
+
+	r.GET("/me/reminders", auth, rh.GetPreferences)
+	r.PUT("/me/reminders", auth, rh.UpdatePreferences)
+
+	r.GET("/me/digest", auth, dh.GetSettings)
+	r.PUT("/me/digest", auth, dh.UpdateSettings)
+	r.GET("/digest/unsubscribe", dh.UnsubscribePage)
+	r.POST("/digest/unsubscribe", dh.Unsubscribe)
+
+	r.GET("/me/notifications", auth, nth.GetNotifications)
+	r.GET("/me/notifications/unread-count", auth, nth.GetUnreadCount)
+	r.PUT("/me/notifications/read", auth, nth.MarkAllRead)
+	r.PUT("/me/notifications/:notificationId/read", auth, nth.MarkRead)
+	r.DELETE("/me/notifications/:notificationId", auth, nth.DeleteNotification)
+
+	r.GET("/me/tokens", auth, tkh.GetTokens)
+	r.POST("/me/tokens", auth, tkh.CreateToken)
+	r.DELETE("/me/tokens/:tokenId", auth, tkh.DeleteToken)
+
+	r.GET("/me/identities", auth, oh.GetIdentities)
+	r.GET("/me/identities/:provider/link", auth, oh.LinkIdentity)
+	r.DELETE("/me/identities/:provider", auth, oh.UnlinkIdentity)
+
+	r.PUT("/admin/users/:userId/disable", auth, admin, adh.DisableUser)
+	r.PUT("/admin/users/:userId/enable", auth, admin, adh.EnableUser)
+	r.PUT("/admin/users/:userId/role", auth, admin, adh.UpdateUserRole)
+	r.POST("/admin/users/:userId/logout", auth, admin, adh.ForceLogout)
+	r.GET("/admin/stats", auth, admin, adh.GetUsageStats)
+
+	r.POST("/auth/refresh", sh.Refresh)
+	r.GET("/auth/sessions", auth, sh.GetSessions)
+	r.DELETE("/auth/sessions/:sessionId", auth, sh.RevokeSession)
+	r.POST("/logout", auth, sh.Logout)
+	r.POST("/logout/all", auth, sh.LogoutAll)
+
+	r.GET("/courses", readCourses, ch.GetCourses)
+	r.GET("/courses/:courseId", readCourses, ch.GetCourseByID)
+	r.POST("/courses", writeCourses, ch.CreateCourse)
+	r.PUT("/courses/:courseId", writeCourses, ch.UpdateCourse)
+	r.DELETE("/courses/:courseId", writeCourses, ch.DeleteCourse)
+	r.POST("/courses/:courseId/export", readCourses, ch.ExportCourse)
+	r.GET("/courses/:courseId/exports/:exportId", readCourses, ch.GetExport)
+
+	r.GET("/courses/tasks", readTasks, th.GetAllTasks)
+	r.GET("/courses/:courseId/tasks", readTasks, th.GetTasksByCourse)
+	r.GET("/courses/:courseId/tasks/:taskId", readTasks, th.GetTaskByID)
+	r.POST("/courses/:courseId/tasks", writeTasks, th.CreateTask)
+	r.PUT("/courses/:courseId/tasks/:taskId", writeTasks, th.UpdateTask)
+	r.PATCH("/courses/:courseId/tasks/:taskId", writeTasks, th.PatchTask)
+	r.PUT("/courses/:courseId/tasks/:taskId/highlight", writeTasks, th.SwitchTaskHighlight)
+	r.PUT("/courses/:courseId/tasks/:taskId/image", writeTasks, th.UploadTaskImage)
+	r.DELETE("/courses/:courseId/tasks/:taskId/image", writeTasks, th.RemoveTaskImage)
+	r.PUT("/courses/:courseId/tasks/:taskId/done", writeTasks, th.MarkTaskDone)
+	r.PUT("/courses/:courseId/tasks/:taskId/undone", writeTasks, th.MarkTaskUndone)
+	r.DELETE("/courses/:courseId/tasks/:taskId", writeTasks, th.DeleteTask)
+
+	r.GET("/courses/:courseId/tasks/:taskId/notes", readTasks, nh.GetNotes)
+	r.GET("/courses/:courseId/tasks/:taskId/notes/:noteId", readTasks, nh.GetNoteByID)
+	r.POST("/courses/:courseId/tasks/:taskId/notes", writeTasks, nh.CreateNote)
+	r.PUT("/courses/:courseId/tasks/:taskId/notes/:noteId", writeTasks, nh.UpdateNote)
+	r.DELETE("/courses/:courseId/tasks/:taskId/notes/:noteId", writeTasks, nh.DeleteNote)
+
+	r.GET("/courses/:courseId/tasks/:taskId/checklist", readTasks, clh.GetChecklist)
+	r.POST("/courses/:courseId/tasks/:taskId/checklist", writeTasks, clh.CreateItem)
+	r.PUT("/courses/:courseId/tasks/:taskId/checklist/order", writeTasks, clh.ReorderItems)
+	r.PATCH("/courses/:courseId/tasks/:taskId/checklist/:itemId", writeTasks, clh.UpdateItem)
+	r.DELETE("/courses/:courseId/tasks/:taskId/checklist/:itemId", writeTasks, clh.DeleteItem)
+
+	r.GET("/courses/:courseId/tasks/:taskId/attachments", readTasks, ah.GetAttachments)
+	r.POST("/courses/:courseId/tasks/:taskId/attachments", writeTasks, ah.UploadAttachment)
+	r.GET("/courses/:courseId/tasks/:taskId/attachments/:attachmentId/download", readTasks, ah.DownloadAttachment)
+	r.DELETE("/courses/:courseId/tasks/:taskId/attachments/:attachmentId", writeTasks, ah.DeleteAttachment)
+	r.GET("/storage/usage", readTasks, ah.GetStorageUsage)
+
+	r.GET("/task-types", readTasks, tth.GetTaskTypes)
+	r.POST("/task-types", writeTasks, tth.CreateTaskType)
+	r.PUT("/task-types/:typeId", writeTasks, tth.UpdateTaskType)
+	r.DELETE("/task-types/:typeId", writeTasks, tth.DeleteTaskType)
 }
 
-func InitHandler(db *sql.DB, rd *redis.Client) (*UserHandler, *CourseHandler, *TaskHandler) {
+func InitHandler(db *sql.DB, rd *redis.Client, blob storage.Blob, providers *oauth.Registry, queue *jobs.Queue) (*UserHandler, *CourseHandler, *TaskHandler, *TaskNoteHandler, *TaskAttachmentHandler, *TaskTypeHandler, *TaskChecklistHandler, *SessionHandler, *ProfileHandler, *AdminHandler, *OAuthHandler, *MFAHandler, *AccessTokenHandler, *ReminderHandler, *DigestHandler, *NotificationHandler) {
 	queries := sqlc.New(db)
 
-	userRepo := repository.NewUserRepository(queries)
-	userServ := service.NewUserService(userRepo, rd)
+	userRepo := repository.NewUserRepository(db)
+	tokenRepo := repository.NewPersonalAccessTokenRepository(queries)
+	sessionServ := service.NewSessionService(userRepo, tokenRepo, rd)
+	sessionHand := NewSessionHandler(sessionServ)
+	mfaRepo := repository.NewUserMFARepository(queries)
+	mfaServ := service.NewMFAService(mfaRepo, userRepo, rd, sessionServ)
+	mfaHand := NewMFAHandler(mfaServ)
+	userServ := service.NewUserService(userRepo, rd, blob, sessionServ, mfaServ, queue)
 	userHand := NewUserHandler(userServ)
+	profileServ := service.NewProfileService(userRepo, rd, blob, userServ, sessionServ, queue)
+	profileHand := NewProfileHandler(profileServ)
+	adminServ := service.NewAdminService(userRepo, rd, userServ, sessionServ)
+	if err := adminServ.PromoteAdmins(strings.Split(os.Getenv("ADMIN_EMAILS"), ",")); err != nil {
+		log.Printf("ADMIN_EMAILS not applied: %v", err)
+	}
+	adminHand := NewAdminHandler(adminServ)
+	tokenServ := service.NewAccessTokenService(tokenRepo, userRepo)
+	tokenHand := NewAccessTokenHandler(tokenServ)
+	identityRepo := repository.NewUserIdentityRepository(queries)
+	oauthServ := service.NewOAuthService(userRepo, identityRepo, rd, providers, mfaServ)
+	oauthHand := NewOAuthHandler(oauthServ)
 
-	courseRepo := repository.NewCourseRepository(queries)
-	courseServ := service.NewCourseService(courseRepo, rd)
+	attachRepo := repository.NewTaskAttachmentRepository(queries)
+
+	notificationRepo := repository.NewNotificationRepository(queries)
+	notificationServ := service.NewNotificationService(notificationRepo)
+	notificationHand := NewNotificationHandler(notificationServ)
+
+	courseRepo := repository.NewCourseRepository(db)
+	courseServ := service.NewCourseService(courseRepo, attachRepo, rd, blob, queue, notificationServ)
 	courseHand := NewCourseHandler(courseServ)
 
-	taskRepo := repository.NewTaskRepository(queries)
-	taskServ := service.NewTaskService(taskRepo, rd, courseServ)
+	taskTypeRepo := repository.NewTaskTypeRepository(queries)
+	taskTypeServ := service.NewTaskTypeService(taskTypeRepo)
+	taskTypeHand := NewTaskTypeHandler(taskTypeServ)
+
+	reminderRepo := repository.NewReminderRepository(queries)
+	reminderServ := service.NewReminderService(reminderRepo)
+	reminderHand := NewReminderHandler(reminderServ)
+
+	taskRepo := repository.NewTaskRepository(db)
+	noteRepo := repository.NewTaskNoteRepository(queries)
+	checklistRepo := repository.NewTaskChecklistRepository(db)
+	taskServ := service.NewTaskService(taskRepo, noteRepo, attachRepo, checklistRepo, rd, blob, courseServ, taskTypeServ, userServ, reminderServ, notificationServ)
 	taskHand := NewTaskHandler(taskServ)
 
-	return userHand, courseHand, taskHand
+	noteServ := service.NewTaskNoteService(noteRepo, taskServ)
+	noteHand := NewTaskNoteHandler(noteServ)
+
+	attachServ := service.NewTaskAttachmentService(attachRepo, rd, blob, taskServ)
+	attachHand := NewTaskAttachmentHandler(attachServ)
+
+	checklistServ := service.NewTaskChecklistService(checklistRepo, taskServ)
+	checklistHand := NewTaskChecklistHandler(checklistServ)
+
+	digestRepo := repository.NewDigestRepository(queries)
+	digestServ := service.NewDigestService(digestRepo)
+	digestHand := NewDigestHandler(digestServ)
+
+	return userHand, courseHand, taskHand, noteHand, attachHand, taskTypeHand, checklistHand, sessionHand, profileHand, adminHand, oauthHand, mfaHand, tokenHand, reminderHand, digestHand, notificationHand
 }
 
-func StartEngine(r *gin.Engine, db *sql.DB, rd *redis.Client) {
-	uh, ch, th := InitHandler(db, rd)
-	route(r, uh, ch, th)
+func StartEngine(r *gin.Engine, db *sql.DB, rd *redis.Client, blob storage.Blob, providers *oauth.Registry, queue *jobs.Queue) {
+	uh, ch, th, nh, ah, tth, clh, sh, ph, adh, oh, mh, tkh, rh, dh, nth := InitHandler(db, rd, blob, providers, queue)
+	route(r, rd, uh, ch, th, nh, ah, tth, clh, sh, ph, adh, oh, mh, tkh, rh, dh, nth)
+
+	if local, ok := blob.(*storage.Local); ok {
+		r.GET("/files/*key", NewFileHandler(local).ServeFile)
+	}
+}
+
+// StartWorkers runs the job queue workers, which deliver emails through m and
+// write course exports to blob, and unless REMINDERS_ENABLED is false the
+// deadline reminder and digest schedulers until ctx is cancelled.
+// REMINDER_INTERVAL and DIGEST_INTERVAL set how often each scheduler scans.
+// Replicas can all run them; sends are deduplicated in Redis. The returned
+// channel is closed once the workers have finished their in-flight jobs.
+func StartWorkers(ctx context.Context, db *sql.DB, rd *redis.Client, blob storage.Blob, queue *jobs.Queue, m mailer.Mailer) <-chan struct{} {
+	queries := sqlc.New(db)
+	notifications := service.NewNotificationService(repository.NewNotificationRepository(queries))
+
+	jobs.Handle(queue, service.SendEmailJob, m.Send)
+	courses := service.NewCourseService(
+		repository.NewCourseRepository(db), repository.NewTaskAttachmentRepository(queries),
+		rd, blob, queue, notifications,
+	)
+	jobs.Handle(queue, service.ExportCourseJob, courses.RunExport)
+
+	if os.Getenv("REMINDERS_ENABLED") != "false" {
+		interval, _ := time.ParseDuration(os.Getenv("REMINDER_INTERVAL"))
+		reminders := service.NewReminderScheduler(
+			repository.NewReminderRepository(queries), rd, interval, notifications,
+			service.NewEmailReminderChannel(queue),
+			service.NewInAppReminderChannel(notifications),
+		)
+		go reminders.Run(ctx)
+
+		interval, _ = time.ParseDuration(os.Getenv("DIGEST_INTERVAL"))
+		digests := service.NewDigestScheduler(
+			repository.NewDigestRepository(queries), repository.NewTaskRepository(db),
+			repository.NewCourseRepository(db), rd, queue, interval,
+		)
+		go digests.Run(ctx)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := queue.Run(ctx); err != nil {
+			log.Printf("Job workers stopped: %v", err)
+		}
+	}()
+	return done
 }
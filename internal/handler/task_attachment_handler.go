@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"courseworker/internal/dto"
+	"courseworker/internal/service"
+	_error "courseworker/pkg/error"
+	"courseworker/pkg/response"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskAttachmentHandler struct {
+	serv service.TaskAttachmentService
+}
+
+func NewTaskAttachmentHandler(s service.TaskAttachmentService) *TaskAttachmentHandler {
+	return &TaskAttachmentHandler{s}
+}
+
+func (h *TaskAttachmentHandler) GetAttachments(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := paramID(c, "courseId")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/GetAttachments"), _error.InvalidRequest,
+			_error.Title("Failed to get attachments"), err,
+		))
+		return
+	}
+
+	resp, err := h.serv.GetAttachments(c, claims.ID, taskID, courseID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, attachmentsFetchSuccess, resp)
+}
+
+func (h *TaskAttachmentHandler) UploadAttachment(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := paramID(c, "courseId")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/UploadAttachment"), _error.InvalidRequest,
+			_error.Title("Failed to upload attachment"), err,
+		))
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/UploadAttachment"), _error.InvalidRequest,
+			_error.Title("Failed to upload attachment"), "file is required",
+		))
+		return
+	}
+
+	resp, err := h.serv.UploadAttachment(c, claims.ID, taskID, courseID, file)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, attachmentUploadSuccess, resp)
+}
+
+func (h *TaskAttachmentHandler) DownloadAttachment(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := paramID(c, "courseId")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/DownloadAttachment"), _error.InvalidRequest,
+			_error.Title("Failed to get attachment"), err,
+		))
+		return
+	}
+	attachmentID, err := paramID(c, "attachmentId")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/DownloadAttachment"), _error.InvalidRequest,
+			_error.Title("Failed to get attachment"), err,
+		))
+		return
+	}
+
+	attachment, obj, err := h.serv.OpenAttachment(c, claims.ID, taskID, courseID, attachmentID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	defer obj.Body.Close()
+
+	c.DataFromReader(http.StatusOK, obj.Size, attachment.ContentType, obj.Body, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", attachment.Filename),
+	})
+}
+
+func (h *TaskAttachmentHandler) DeleteAttachment(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := paramID(c, "courseId")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/DeleteAttachment"), _error.InvalidRequest,
+			_error.Title("Failed to delete attachment"), err,
+		))
+		return
+	}
+	attachmentID, err := paramID(c, "attachmentId")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/DeleteAttachment"), _error.InvalidRequest,
+			_error.Title("Failed to delete attachment"), err,
+		))
+		return
+	}
+
+	if err := h.serv.DeleteAttachment(c, claims.ID, taskID, courseID, attachmentID); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, attachmentDeleteSuccess, nil)
+}
+
+func (h *TaskAttachmentHandler) GetStorageUsage(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	resp, err := h.serv.GetStorageUsage(c, claims.ID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, storageUsageFetchSuccess, resp)
+}
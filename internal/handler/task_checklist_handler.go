@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"courseworker/internal/dto"
+	"courseworker/internal/service"
+	_error "courseworker/pkg/error"
+	"courseworker/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskChecklistHandler struct {
+	serv service.TaskChecklistService
+}
+
+func NewTaskChecklistHandler(s service.TaskChecklistService) *TaskChecklistHandler {
+	return &TaskChecklistHandler{s}
+}
+
+func (h *TaskChecklistHandler) GetChecklist(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := paramID(c, "courseId")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/GetChecklist"), _error.InvalidRequest,
+			_error.Title("Failed to get checklist"), err,
+		))
+		return
+	}
+
+	resp, err := h.serv.GetChecklist(c, claims.ID, taskID, courseID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, checklistFetchSuccess, resp)
+}
+
+func (h *TaskChecklistHandler) CreateItem(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := paramID(c, "courseId")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/CreateChecklistItem"), _error.InvalidRequest,
+			_error.Title("Failed to create checklist item"), err,
+		))
+		return
+	}
+
+	var req dto.ChecklistItemCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.CreateItem(c, claims.ID, taskID, courseID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, checklistItemCreateSuccess, resp)
+}
+
+func (h *TaskChecklistHandler) UpdateItem(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, itemID, err := parseChecklistParams(c)
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/UpdateChecklistItem"), _error.InvalidRequest,
+			_error.Title("Failed to update checklist item"), err,
+		))
+		return
+	}
+
+	var req dto.ChecklistItemUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.UpdateItem(c, claims.ID, taskID, courseID, itemID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, checklistItemUpdateSuccess, resp)
+}
+
+func (h *TaskChecklistHandler) DeleteItem(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, itemID, err := parseChecklistParams(c)
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/DeleteChecklistItem"), _error.InvalidRequest,
+			_error.Title("Failed to delete checklist item"), err,
+		))
+		return
+	}
+
+	if err := h.serv.DeleteItem(c, claims.ID, taskID, courseID, itemID); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, checklistItemDeleteSuccess, nil)
+}
+
+func (h *TaskChecklistHandler) ReorderItems(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := paramID(c, "courseId")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/ReorderChecklistItems"), _error.InvalidRequest,
+			_error.Title("Failed to reorder checklist"), err,
+		))
+		return
+	}
+
+	var req dto.ChecklistReorderReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.ReorderItems(c, claims.ID, taskID, courseID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, checklistReorderSuccess, resp)
+}
+
+func parseChecklistParams(c *gin.Context) (courseID, itemID int64, err error) {
+	if courseID, err = paramID(c, "courseId"); err != nil {
+		return 0, 0, err
+	}
+	if itemID, err = paramID(c, "itemId"); err != nil {
+		return 0, 0, err
+	}
+	return courseID, itemID, nil
+}
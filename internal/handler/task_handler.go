@@ -23,12 +23,18 @@ func (h *TaskHandler) GetAllTasks(c *gin.Context) {
 	auth, _ := c.Get("user")
 	claims := auth.(*dto.UserClaims)
 
-	resp, err := h.serv.GetAllTasksOfUser(claims.ID)
+	var query dto.TaskListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.HttpBindingError(c, err, query)
+		return
+	}
+
+	resp, meta, err := h.serv.GetAllTasksOfUser(c, claims.ID, query)
 	if err != nil {
 		response.HttpError(c, err)
 		return
 	}
-	response.Success(c, http.StatusOK, tasksFetchSuccess, resp)
+	response.SuccessWithMeta(c, http.StatusOK, tasksFetchSuccess, resp, meta)
 }
 
 func (h *TaskHandler) GetTasksByCourse(c *gin.Context) {
@@ -44,12 +50,18 @@ func (h *TaskHandler) GetTasksByCourse(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.serv.GetTasksByCourseID(c, claims.ID, int64(courseID))
+	var query dto.TaskListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.HttpBindingError(c, err, query)
+		return
+	}
+
+	resp, meta, err := h.serv.GetTasksByCourseID(c, claims.ID, int64(courseID), query)
 	if err != nil {
 		response.HttpError(c, err)
 		return
 	}
-	response.Success(c, http.StatusOK, tasksFetchSuccess, resp)
+	response.SuccessWithMeta(c, http.StatusOK, tasksFetchSuccess, resp, meta)
 }
 
 func (h *TaskHandler) GetTaskByID(c *gin.Context) {
@@ -66,7 +78,7 @@ func (h *TaskHandler) GetTaskByID(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.serv.GetTaskByID(c, claims.ID, taskID, int64(courseID))
+	resp, err := h.serv.GetTaskByID(c, claims.ID, taskID, int64(courseID), hasInclude(c, "notes"))
 	if err != nil {
 		response.HttpError(c, err)
 		return
@@ -100,6 +112,74 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 	response.Success(c, http.StatusCreated, taskCreateSuccess, resp)
 }
 
+func (h *TaskHandler) UpdateTask(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := strconv.Atoi(c.Param("courseId"))
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/UpdateTask"), _error.InvalidRequest,
+			_error.Title("Failed to update task"), "courseId must be a number",
+		))
+		return
+	}
+
+	var scope dto.TaskScopeQuery
+	if err := c.ShouldBindQuery(&scope); err != nil {
+		response.HttpBindingError(c, err, scope)
+		return
+	}
+
+	var req dto.TaskUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.UpdateTask(c, claims.ID, taskID, int64(courseID), req, scope.Scope)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, taskUpdateSuccess, resp)
+}
+
+func (h *TaskHandler) PatchTask(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := strconv.Atoi(c.Param("courseId"))
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/PatchTask"), _error.InvalidRequest,
+			_error.Title("Failed to update task"), "courseId must be a number",
+		))
+		return
+	}
+
+	var scope dto.TaskScopeQuery
+	if err := c.ShouldBindQuery(&scope); err != nil {
+		response.HttpBindingError(c, err, scope)
+		return
+	}
+
+	var req dto.TaskPatchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.PatchTask(c, claims.ID, taskID, int64(courseID), req, scope.Scope)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, taskUpdateSuccess, resp)
+}
+
 func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	auth, _ := c.Get("user")
 	claims := auth.(*dto.UserClaims)
@@ -114,7 +194,13 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 		return
 	}
 
-	if err = h.serv.DeleteTask(c, claims.ID, taskID, int64(courseID)); err != nil {
+	var scope dto.TaskScopeQuery
+	if err := c.ShouldBindQuery(&scope); err != nil {
+		response.HttpBindingError(c, err, scope)
+		return
+	}
+
+	if err = h.serv.DeleteTask(c, claims.ID, taskID, int64(courseID), scope.Scope); err != nil {
 		response.HttpError(c, err)
 		return
 	}
@@ -142,3 +228,86 @@ func (h *TaskHandler) SwitchTaskHighlight(c *gin.Context) {
 	}
 	response.Success(c, http.StatusOK, taskUpdateSuccess, resp)
 }
+
+func (h *TaskHandler) MarkTaskDone(c *gin.Context) {
+	h.setTaskDone(c, true)
+}
+
+func (h *TaskHandler) MarkTaskUndone(c *gin.Context) {
+	h.setTaskDone(c, false)
+}
+
+func (h *TaskHandler) setTaskDone(c *gin.Context, done bool) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := strconv.Atoi(c.Param("courseId"))
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/setTaskDone"), _error.InvalidRequest,
+			_error.Title("Failed to update task"), "courseId must be a number",
+		))
+		return
+	}
+
+	resp, err := h.serv.SetTaskDone(c, claims.ID, taskID, int64(courseID), done)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, taskUpdateSuccess, resp)
+}
+
+func (h *TaskHandler) UploadTaskImage(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := strconv.Atoi(c.Param("courseId"))
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/UploadTaskImage"), _error.InvalidRequest,
+			_error.Title("Failed to upload image"), "courseId must be a number",
+		))
+		return
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/UploadTaskImage"), _error.InvalidRequest,
+			_error.Title("Failed to upload image"), "image file is required",
+		))
+		return
+	}
+
+	resp, err := h.serv.UploadTaskImage(c, claims.ID, taskID, int64(courseID), file)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, taskImageUploadSuccess, resp)
+}
+
+func (h *TaskHandler) RemoveTaskImage(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := strconv.Atoi(c.Param("courseId"))
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/RemoveTaskImage"), _error.InvalidRequest,
+			_error.Title("Failed to remove image"), "courseId must be a number",
+		))
+		return
+	}
+
+	resp, err := h.serv.RemoveTaskImage(c, claims.ID, taskID, int64(courseID))
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, taskImageRemoveSuccess, resp)
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"courseworker/internal/dto"
+	"courseworker/internal/service"
+	_error "courseworker/pkg/error"
+	"courseworker/pkg/response"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskNoteHandler struct {
+	serv service.TaskNoteService
+}
+
+func NewTaskNoteHandler(s service.TaskNoteService) *TaskNoteHandler {
+	return &TaskNoteHandler{s}
+}
+
+func (h *TaskNoteHandler) GetNotes(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := strconv.Atoi(c.Param("courseId"))
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/GetNotes"), _error.InvalidRequest,
+			_error.Title("Failed to get notes"), "courseId must be a number",
+		))
+		return
+	}
+
+	page, limit, err := parsePage(c)
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/GetNotes"), _error.InvalidRequest,
+			_error.Title("Failed to get notes"), err,
+		))
+		return
+	}
+
+	resp, err := h.serv.GetNotesOfTask(c, claims.ID, taskID, int64(courseID), page, limit)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, notesFetchSuccess, resp)
+}
+
+func (h *TaskNoteHandler) GetNoteByID(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, noteID, err := parseNoteParams(c)
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/GetNoteByID"), _error.InvalidRequest,
+			_error.Title("Failed to get note"), err,
+		))
+		return
+	}
+
+	resp, err := h.serv.GetNoteByID(c, claims.ID, taskID, courseID, noteID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, noteFetchSuccess, resp)
+}
+
+func (h *TaskNoteHandler) CreateNote(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, err := strconv.Atoi(c.Param("courseId"))
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/CreateNote"), _error.InvalidRequest,
+			_error.Title("Failed to create note"), "courseId must be a number",
+		))
+		return
+	}
+
+	var req dto.TaskNoteCreateUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.CreateNote(c, claims.ID, taskID, int64(courseID), req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, noteCreateSuccess, resp)
+}
+
+func (h *TaskNoteHandler) UpdateNote(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, noteID, err := parseNoteParams(c)
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/UpdateNote"), _error.InvalidRequest,
+			_error.Title("Failed to update note"), err,
+		))
+		return
+	}
+
+	var req dto.TaskNoteCreateUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.UpdateNote(c, claims.ID, taskID, courseID, noteID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, noteUpdateSuccess, resp)
+}
+
+func (h *TaskNoteHandler) DeleteNote(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	taskID := c.Param("taskId")
+	courseID, noteID, err := parseNoteParams(c)
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/DeleteNote"), _error.InvalidRequest,
+			_error.Title("Failed to delete note"), err,
+		))
+		return
+	}
+
+	if err := h.serv.DeleteNote(c, claims.ID, taskID, courseID, noteID); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, noteDeleteSuccess, nil)
+}
+
+func parseNoteParams(c *gin.Context) (courseID, noteID int64, err error) {
+	courseID, err = strconv.ParseInt(c.Param("courseId"), 10, 64)
+	if err != nil {
+		return 0, 0, errors.New("courseId must be a number")
+	}
+	noteID, err = strconv.ParseInt(c.Param("noteId"), 10, 64)
+	if err != nil {
+		return 0, 0, errors.New("noteId must be a number")
+	}
+	return courseID, noteID, nil
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"courseworker/internal/dto"
+	"courseworker/internal/service"
+	_error "courseworker/pkg/error"
+	"courseworker/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskTypeHandler struct {
+	serv service.TaskTypeService
+}
+
+func NewTaskTypeHandler(s service.TaskTypeService) *TaskTypeHandler {
+	return &TaskTypeHandler{s}
+}
+
+func (h *TaskTypeHandler) GetTaskTypes(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	resp, err := h.serv.GetTaskTypes(c, claims.ID)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, taskTypesFetchSuccess, resp)
+}
+
+func (h *TaskTypeHandler) CreateTaskType(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var req dto.TaskTypeCreateUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.CreateTaskType(c, claims.ID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusCreated, taskTypeCreateSuccess, resp)
+}
+
+func (h *TaskTypeHandler) UpdateTaskType(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	typeID, err := paramID(c, "typeId")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/UpdateTaskType"), _error.InvalidRequest,
+			_error.Title("Failed to update task type"), err,
+		))
+		return
+	}
+
+	var req dto.TaskTypeCreateUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	resp, err := h.serv.UpdateTaskType(c, claims.ID, typeID, req)
+	if err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, taskTypeUpdateSuccess, resp)
+}
+
+func (h *TaskTypeHandler) DeleteTaskType(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	typeID, err := paramID(c, "typeId")
+	if err != nil {
+		response.HttpError(c, _error.E(
+			_error.Op("hand/DeleteTaskType"), _error.InvalidRequest,
+			_error.Title("Failed to delete task type"), err,
+		))
+		return
+	}
+
+	if err := h.serv.DeleteTaskType(c, claims.ID, typeID); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, taskTypeDeleteSuccess, nil)
+}
@@ -1,22 +1,13 @@
 package handler
 
 import (
-	"context"
 	"courseworker/internal/dto"
 	"courseworker/internal/service"
 	_error "courseworker/pkg/error"
 	"courseworker/pkg/response"
-	"crypto/rand"
-	"encoding/base64"
-	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
-	"os"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
 )
 
 type UserHandler struct {
@@ -28,98 +19,52 @@ func NewUserHandler(s service.UserService) *UserHandler {
 }
 
 func (h *UserHandler) GetUsers(c *gin.Context) {
-	resp, err := h.serv.GetUsers()
-	if err != nil {
-		response.HttpError(c, err)
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
+
+	var query dto.UserListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.HttpBindingError(c, err, query)
 		return
 	}
-	response.Success(c, http.StatusOK, usersFetchSuccess, resp)
-}
 
-func (h *UserHandler) GetUserByID(c *gin.Context) {
-	userID := c.Param("userId")
-	resp, err := h.serv.GetUserByID(userID)
+	resp, meta, err := h.serv.GetUsers(c, claims, query)
 	if err != nil {
 		response.HttpError(c, err)
 		return
 	}
-	response.Success(c, http.StatusOK, userFetchSuccess, resp)
-}
-
-var googleOauthConfig = &oauth2.Config{
-	RedirectURL: os.Getenv("BASE_URL") + "/auth/google/callback",
-	Scopes: []string{
-		"https://www.googleapis.com/auth/userinfo.email",
-		"https://www.googleapis.com/auth/userinfo.profile",
-	},
-	Endpoint: google.Endpoint,
-}
-
-func (*UserHandler) LoginWithGoogle(c *gin.Context) {
-	googleOauthConfig.ClientID = os.Getenv("CLIENT_ID")
-	googleOauthConfig.ClientSecret = os.Getenv("CLIENT_SECRET")
-
-	oauthState := generateStateOauthCookie()
-	authURL := googleOauthConfig.AuthCodeURL(oauthState)
-
-	fmt.Println("url = " + authURL)
-
-	// in string
-	c.String(http.StatusOK, authURL)
+	response.SuccessWithMeta(c, http.StatusOK, usersFetchSuccess, resp, meta)
 }
 
-func generateStateOauthCookie() string {
-	b := make([]byte, 16)
-	rand.Read(b)
-	state := base64.URLEncoding.EncodeToString(b)
-
-	return state
-}
-
-func (h *UserHandler) GetGoogleDetails(c *gin.Context) {
-	token, err := googleOauthConfig.Exchange(context.Background(), c.Request.FormValue("code"))
-	if err != nil {
-		response.HttpError(c, err)
-		return
-	}
+func (h *UserHandler) GetUserByID(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
 
-	rsp, err := http.Get("https://www.googleapis.com/oauth2/v2/userinfo?access_token=" + token.AccessToken)
+	userID := c.Param("userId")
+	resp, err := h.serv.GetUser(c, claims, userID)
 	if err != nil {
 		response.HttpError(c, err)
 		return
 	}
+	response.Success(c, http.StatusOK, userFetchSuccess, resp)
+}
 
-	content, err := io.ReadAll(rsp.Body)
-	if err != nil {
-		response.HttpError(c, err)
-		return
-	}
+func (h *UserHandler) UpdateTimezone(c *gin.Context) {
+	auth, _ := c.Get("user")
+	claims := auth.(*dto.UserClaims)
 
-	var container dto.AuthenticatedUser
-	err = json.Unmarshal(content, &container)
-	if err != nil {
-		response.HttpError(c, err)
+	var req dto.UserTimezoneReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
 		return
 	}
 
-	isExist, err := h.serv.EmailExists(container.Email)
-	if err != nil {
-		response.HttpError(c, err)
-	}
-	if !isExist {
-		h.serv.CreateUser(dto.CreateUserParams{
-			Name:     container.Name,
-			Email:    container.Email,
-			HashedPw: "",
-		})
-	}
-
-	resp, err := h.serv.GenerateToken(container.Email)
+	resp, err := h.serv.UpdateTimezone(c, claims.ID, req)
 	if err != nil {
 		response.HttpError(c, err)
 		return
 	}
-	response.Success(c, http.StatusOK, userLoginSuccess, resp)
+	response.Success(c, http.StatusOK, userTimezoneUpdateSuccess, resp)
 }
 
 func (h *UserHandler) RegisterUser(c *gin.Context) {
@@ -202,10 +147,42 @@ func (h *UserHandler) LoginUser(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.serv.LoginUser(req)
+	resp, err := h.serv.LoginUser(c, req)
 	if err != nil {
 		response.HttpError(c, err)
 		return
 	}
+	if resp.MFARequired {
+		response.Success(c, http.StatusOK, mfaRequired, resp)
+		return
+	}
 	response.Success(c, http.StatusOK, userLoginSuccess, resp)
 }
+
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	if err := h.serv.ForgotPassword(c, req); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, passwordForgotSuccess, nil)
+}
+
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.HttpBindingError(c, err, req)
+		return
+	}
+
+	if err := h.serv.ResetPassword(c, req); err != nil {
+		response.HttpError(c, err)
+		return
+	}
+	response.Success(c, http.StatusOK, passwordResetSuccess, nil)
+}
@@ -14,16 +14,19 @@ type CourseRepository interface {
 	GetCourseByID(ID int64) (*sqlc.Course, error)
 	CreateCourse(param sqlc.CreateCourseParams) (sql.Result, error)
 	UpdateCourse(param sqlc.UpdateCourseParams) (sql.Result, error)
-	DeleteCourse(courseID int64) (sql.Result, error)
+	DeleteCourse(courseID int64) error
 	GetUserIDFromCourse(courseID int64) (string, error)
+	GetTaskIDsByCourse(courseID int64) ([]string, error)
+	GetTasksByCourse(courseID int64) ([]sqlc.Task, error)
 }
 
 type courseRepository struct {
-	db *sqlc.Queries
+	db   *sqlc.Queries
+	conn *sql.DB
 }
 
-func NewCourseRepository(db *sqlc.Queries) CourseRepository {
-	return &courseRepository{db}
+func NewCourseRepository(conn *sql.DB) CourseRepository {
+	return &courseRepository{db: sqlc.New(conn), conn: conn}
 }
 
 func (r *courseRepository) GetAllCourses(userID string) ([]sqlc.Course, error) {
@@ -68,23 +71,47 @@ func (r *courseRepository) UpdateCourse(param sqlc.UpdateCourseParams) (sql.Resu
 	return result, nil
 }
 
-func (r *courseRepository) DeleteCourse(courseID int64) (sql.Result, error) {
+// DeleteCourse deletes the course with its tasks and, in the same
+// transaction, the series those tasks leave empty. Series with occurrences
+// moved to another course are kept.
+func (r *courseRepository) DeleteCourse(courseID int64) error {
 	const op _error.Op = "repo/DeleteCourse"
-	result, err := r.db.DeleteCourse(context.Background(), courseID)
-	if err != nil {
-		return nil, _error.E(op, _error.Database, err)
-	}
-	affected, err := result.RowsAffected()
-	if err != nil {
-		return nil, _error.E(op, _error.Database, err)
-	}
-	if affected == 0 {
-		return nil, _error.E(
+	ctx := context.Background()
+	missing := false
+	err := inTx(r.conn, func(q *sqlc.Queries) error {
+		seriesIDs, err := q.GetTaskSeriesIDsByCourse(ctx, courseID)
+		if err != nil {
+			return err
+		}
+		result, err := q.DeleteCourse(ctx, courseID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			missing = true
+			return sql.ErrNoRows
+		}
+		for _, id := range seriesIDs {
+			if _, err := q.DeleteEmptyTaskSeries(ctx, id.String); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if missing {
+		return _error.E(
 			op, _error.Title("No row affected"),
 			fmt.Sprintf("The requested course with id %d could not be found", courseID),
 		)
 	}
-	return result, nil
+	if err != nil {
+		return _error.E(op, _error.Database, err)
+	}
+	return nil
 }
 
 func (r *courseRepository) GetUserIDFromCourse(courseID int64) (string, error) {
@@ -101,3 +128,24 @@ func (r *courseRepository) GetUserIDFromCourse(courseID int64) (string, error) {
 	}
 	return result, nil
 }
+
+func (r *courseRepository) GetTaskIDsByCourse(courseID int64) ([]string, error) {
+	const op _error.Op = "repo/GetTaskIDsByCourse"
+	result, err := r.db.GetTaskIDsByCourse(context.Background(), courseID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []string{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *courseRepository) GetTasksByCourse(courseID int64) ([]sqlc.Task, error) {
+	const op _error.Op = "repo/GetTasksByCourse"
+	result, err := r.db.GetTasksByCourseID(context.Background(), courseID)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
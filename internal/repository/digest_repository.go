@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	_error "courseworker/pkg/error"
+	"database/sql"
+	"errors"
+)
+
+type DigestRepository interface {
+	GetDigestSubscription(userID string) (*sqlc.DigestSubscription, error)
+	GetActiveDigestSubscriptions() ([]sqlc.GetActiveDigestSubscriptionsRow, error)
+	UpsertDigestSubscription(param sqlc.UpsertDigestSubscriptionParams) (sql.Result, error)
+	UnsubscribeDigest(userID string) (sql.Result, error)
+	SetDigestSentAt(param sqlc.SetDigestSentAtParams) (sql.Result, error)
+}
+
+type digestRepository struct {
+	db *sqlc.Queries
+}
+
+func NewDigestRepository(db *sqlc.Queries) DigestRepository {
+	return &digestRepository{db}
+}
+
+func (r *digestRepository) GetDigestSubscription(userID string) (*sqlc.DigestSubscription, error) {
+	const op _error.Op = "repo/GetDigestSubscription"
+	result, err := r.db.GetDigestSubscription(context.Background(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, _error.E(
+				op, _error.NotExist, _error.Title("Digest subscription not found"),
+				"Digest settings have not been set",
+			)
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return &result, nil
+}
+
+func (r *digestRepository) GetActiveDigestSubscriptions() ([]sqlc.GetActiveDigestSubscriptionsRow, error) {
+	const op _error.Op = "repo/GetActiveDigestSubscriptions"
+	result, err := r.db.GetActiveDigestSubscriptions(context.Background())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []sqlc.GetActiveDigestSubscriptionsRow{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *digestRepository) UpsertDigestSubscription(param sqlc.UpsertDigestSubscriptionParams) (sql.Result, error) {
+	const op _error.Op = "repo/UpsertDigestSubscription"
+	result, err := r.db.UpsertDigestSubscription(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *digestRepository) UnsubscribeDigest(userID string) (sql.Result, error) {
+	const op _error.Op = "repo/UnsubscribeDigest"
+	result, err := r.db.UnsubscribeDigest(context.Background(), userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *digestRepository) SetDigestSentAt(param sqlc.SetDigestSentAtParams) (sql.Result, error) {
+	const op _error.Op = "repo/SetDigestSentAt"
+	result, err := r.db.SetDigestSentAt(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
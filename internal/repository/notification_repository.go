@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	_error "courseworker/pkg/error"
+	"database/sql"
+	"errors"
+)
+
+type NotificationRepository interface {
+	GetNotificationsByUserID(param sqlc.GetNotificationsByUserIDParams) ([]sqlc.Notification, error)
+	CountNotificationsByUserID(param sqlc.CountNotificationsByUserIDParams) (int64, error)
+	CountUnreadNotifications(userID string) (int64, error)
+	GetNotificationByID(param sqlc.GetNotificationByIDParams) (*sqlc.Notification, error)
+	CreateNotification(param sqlc.CreateNotificationParams) (sql.Result, error)
+	MarkNotificationRead(param sqlc.MarkNotificationReadParams) (sql.Result, error)
+	MarkAllNotificationsRead(userID string) (int64, error)
+	DeleteNotification(param sqlc.DeleteNotificationParams) (sql.Result, error)
+}
+
+type notificationRepository struct {
+	db *sqlc.Queries
+}
+
+func NewNotificationRepository(db *sqlc.Queries) NotificationRepository {
+	return &notificationRepository{db}
+}
+
+func (r *notificationRepository) GetNotificationsByUserID(param sqlc.GetNotificationsByUserIDParams) ([]sqlc.Notification, error) {
+	const op _error.Op = "repo/GetNotificationsByUserID"
+	result, err := r.db.GetNotificationsByUserID(context.Background(), param)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []sqlc.Notification{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *notificationRepository) CountNotificationsByUserID(param sqlc.CountNotificationsByUserIDParams) (int64, error) {
+	const op _error.Op = "repo/CountNotificationsByUserID"
+	result, err := r.db.CountNotificationsByUserID(context.Background(), param)
+	if err != nil {
+		return -1, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *notificationRepository) CountUnreadNotifications(userID string) (int64, error) {
+	const op _error.Op = "repo/CountUnreadNotifications"
+	result, err := r.db.CountUnreadNotifications(context.Background(), userID)
+	if err != nil {
+		return -1, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *notificationRepository) GetNotificationByID(param sqlc.GetNotificationByIDParams) (*sqlc.Notification, error) {
+	const op _error.Op = "repo/GetNotificationByID"
+	result, err := r.db.GetNotificationByID(context.Background(), param)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, _error.E(
+				op, _error.NotExist, _error.Title("Notification not found"),
+				"Notification does not exist",
+			)
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return &result, nil
+}
+
+func (r *notificationRepository) CreateNotification(param sqlc.CreateNotificationParams) (sql.Result, error) {
+	const op _error.Op = "repo/CreateNotification"
+	result, err := r.db.CreateNotification(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *notificationRepository) MarkNotificationRead(param sqlc.MarkNotificationReadParams) (sql.Result, error) {
+	const op _error.Op = "repo/MarkNotificationRead"
+	result, err := r.db.MarkNotificationRead(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+// MarkAllNotificationsRead returns how many notifications were unread.
+func (r *notificationRepository) MarkAllNotificationsRead(userID string) (int64, error) {
+	const op _error.Op = "repo/MarkAllNotificationsRead"
+	result, err := r.db.MarkAllNotificationsRead(context.Background(), userID)
+	if err != nil {
+		return -1, _error.E(op, _error.Database, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return -1, _error.E(op, _error.Database, err)
+	}
+	return affected, nil
+}
+
+func (r *notificationRepository) DeleteNotification(param sqlc.DeleteNotificationParams) (sql.Result, error) {
+	const op _error.Op = "repo/DeleteNotification"
+	result, err := r.db.DeleteNotification(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	if affected == 0 {
+		return nil, _error.E(
+			op, _error.NotExist, _error.Title("No row affected"),
+			"Notification does not exist",
+		)
+	}
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	_error "courseworker/pkg/error"
+	"database/sql"
+	"errors"
+)
+
+type PersonalAccessTokenRepository interface {
+	GetPersonalAccessTokenByHash(tokenHash string) (*sqlc.PersonalAccessToken, error)
+	GetPersonalAccessTokensByUserID(userID string) ([]sqlc.PersonalAccessToken, error)
+	CreatePersonalAccessToken(param sqlc.CreatePersonalAccessTokenParams) (sql.Result, error)
+	TouchPersonalAccessToken(id string) (sql.Result, error)
+	DeletePersonalAccessToken(param sqlc.DeletePersonalAccessTokenParams) (sql.Result, error)
+	DeletePersonalAccessTokensByUserID(userID string) (sql.Result, error)
+}
+
+type personalAccessTokenRepository struct {
+	db *sqlc.Queries
+}
+
+func NewPersonalAccessTokenRepository(db *sqlc.Queries) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{db}
+}
+
+func (r *personalAccessTokenRepository) GetPersonalAccessTokenByHash(tokenHash string) (*sqlc.PersonalAccessToken, error) {
+	const op _error.Op = "repo/GetPersonalAccessTokenByHash"
+	result, err := r.db.GetPersonalAccessTokenByHash(context.Background(), tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, _error.E(
+				op, _error.NotExist, _error.Title("Token not found"),
+				"Access token does not exist",
+			)
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return &result, nil
+}
+
+func (r *personalAccessTokenRepository) GetPersonalAccessTokensByUserID(userID string) ([]sqlc.PersonalAccessToken, error) {
+	const op _error.Op = "repo/GetPersonalAccessTokensByUserID"
+	result, err := r.db.GetPersonalAccessTokensByUserID(context.Background(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []sqlc.PersonalAccessToken{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *personalAccessTokenRepository) CreatePersonalAccessToken(param sqlc.CreatePersonalAccessTokenParams) (sql.Result, error) {
+	const op _error.Op = "repo/CreatePersonalAccessToken"
+	result, err := r.db.CreatePersonalAccessToken(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *personalAccessTokenRepository) TouchPersonalAccessToken(id string) (sql.Result, error) {
+	const op _error.Op = "repo/TouchPersonalAccessToken"
+	result, err := r.db.TouchPersonalAccessToken(context.Background(), id)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *personalAccessTokenRepository) DeletePersonalAccessToken(param sqlc.DeletePersonalAccessTokenParams) (sql.Result, error) {
+	const op _error.Op = "repo/DeletePersonalAccessToken"
+	result, err := r.db.DeletePersonalAccessToken(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	if affected == 0 {
+		return nil, _error.E(
+			op, _error.NotExist, _error.Title("No row affected"),
+			"Access token does not exist",
+		)
+	}
+	return result, nil
+}
+
+func (r *personalAccessTokenRepository) DeletePersonalAccessTokensByUserID(userID string) (sql.Result, error) {
+	const op _error.Op = "repo/DeletePersonalAccessTokensByUserID"
+	result, err := r.db.DeletePersonalAccessTokensByUserID(context.Background(), userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
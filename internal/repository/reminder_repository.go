@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	_error "courseworker/pkg/error"
+	"database/sql"
+	"errors"
+)
+
+type ReminderRepository interface {
+	GetReminderPreferences(userID string) (*sqlc.ReminderPreference, error)
+	UpsertReminderPreferences(param sqlc.UpsertReminderPreferencesParams) (sql.Result, error)
+	GetUpcomingDeadlines(param sqlc.GetUpcomingDeadlinesParams) ([]sqlc.GetUpcomingDeadlinesRow, error)
+}
+
+type reminderRepository struct {
+	db *sqlc.Queries
+}
+
+func NewReminderRepository(db *sqlc.Queries) ReminderRepository {
+	return &reminderRepository{db}
+}
+
+func (r *reminderRepository) GetReminderPreferences(userID string) (*sqlc.ReminderPreference, error) {
+	const op _error.Op = "repo/GetReminderPreferences"
+	result, err := r.db.GetReminderPreferences(context.Background(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, _error.E(
+				op, _error.NotExist, _error.Title("Reminder preferences not found"),
+				"Reminder preferences have not been set",
+			)
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return &result, nil
+}
+
+func (r *reminderRepository) UpsertReminderPreferences(param sqlc.UpsertReminderPreferencesParams) (sql.Result, error) {
+	const op _error.Op = "repo/UpsertReminderPreferences"
+	result, err := r.db.UpsertReminderPreferences(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *reminderRepository) GetUpcomingDeadlines(param sqlc.GetUpcomingDeadlinesParams) ([]sqlc.GetUpcomingDeadlinesRow, error) {
+	const op _error.Op = "repo/GetUpcomingDeadlines"
+	result, err := r.db.GetUpcomingDeadlines(context.Background(), param)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []sqlc.GetUpcomingDeadlinesRow{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
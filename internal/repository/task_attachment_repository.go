@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	_error "courseworker/pkg/error"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type TaskAttachmentRepository interface {
+	GetAttachmentsByTaskIDs(taskIDs []string) ([]sqlc.TaskAttachment, error)
+	GetAttachmentByID(param sqlc.GetAttachmentByIDParams) (*sqlc.TaskAttachment, error)
+	GetAttachmentBlobKeysByCourse(courseID int64) ([]string, error)
+	CountAttachmentsByBlobKey(blobKey string) (int64, error)
+	GetUserStorageUsage(userID string) (int64, error)
+	CreateAttachment(param sqlc.CreateAttachmentParams) (sql.Result, error)
+	DeleteAttachment(param sqlc.DeleteAttachmentParams) (sql.Result, error)
+}
+
+type taskAttachmentRepository struct {
+	db *sqlc.Queries
+}
+
+func NewTaskAttachmentRepository(db *sqlc.Queries) TaskAttachmentRepository {
+	return &taskAttachmentRepository{db}
+}
+
+func (r *taskAttachmentRepository) GetAttachmentsByTaskIDs(taskIDs []string) ([]sqlc.TaskAttachment, error) {
+	const op _error.Op = "repo/GetAttachmentsByTaskIDs"
+	if len(taskIDs) == 0 {
+		return []sqlc.TaskAttachment{}, nil
+	}
+	result, err := r.db.GetAttachmentsByTaskIDs(context.Background(), taskIDs)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []sqlc.TaskAttachment{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskAttachmentRepository) GetAttachmentByID(param sqlc.GetAttachmentByIDParams) (*sqlc.TaskAttachment, error) {
+	const op _error.Op = "repo/GetAttachmentByID"
+	result, err := r.db.GetAttachmentByID(context.Background(), param)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, _error.E(
+				op, _error.NotExist, _error.Title("Attachment not found"),
+				fmt.Sprintf("The requested attachment with id %d could not be found", param.ID),
+			)
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return &result, nil
+}
+
+func (r *taskAttachmentRepository) GetAttachmentBlobKeysByCourse(courseID int64) ([]string, error) {
+	const op _error.Op = "repo/GetAttachmentBlobKeysByCourse"
+	result, err := r.db.GetAttachmentBlobKeysByCourse(context.Background(), courseID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []string{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskAttachmentRepository) CountAttachmentsByBlobKey(blobKey string) (int64, error) {
+	const op _error.Op = "repo/CountAttachmentsByBlobKey"
+	result, err := r.db.CountAttachmentsByBlobKey(context.Background(), blobKey)
+	if err != nil {
+		return -1, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskAttachmentRepository) GetUserStorageUsage(userID string) (int64, error) {
+	const op _error.Op = "repo/GetUserStorageUsage"
+	result, err := r.db.GetUserStorageUsage(context.Background(), userID)
+	if err != nil {
+		return -1, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskAttachmentRepository) CreateAttachment(param sqlc.CreateAttachmentParams) (sql.Result, error) {
+	const op _error.Op = "repo/CreateAttachment"
+	result, err := r.db.CreateAttachment(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskAttachmentRepository) DeleteAttachment(param sqlc.DeleteAttachmentParams) (sql.Result, error) {
+	const op _error.Op = "repo/DeleteAttachment"
+	result, err := r.db.DeleteAttachment(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	if affected == 0 {
+		return nil, _error.E(
+			op, _error.NotExist, _error.Title("No row affected"),
+			fmt.Sprintf("The requested attachment with id %d could not be found", param.ID),
+		)
+	}
+	return result, nil
+}
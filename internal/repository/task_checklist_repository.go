@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	_error "courseworker/pkg/error"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type TaskChecklistRepository interface {
+	GetItemsByTaskID(taskID string) ([]sqlc.TaskChecklistItem, error)
+	GetItemByID(param sqlc.GetChecklistItemByIDParams) (*sqlc.TaskChecklistItem, error)
+	GetMaxPosition(taskID string) (int64, error)
+	GetProgressByTaskIDs(taskIDs []string) ([]sqlc.GetChecklistProgressByTaskIDsRow, error)
+	CreateItem(param sqlc.CreateChecklistItemParams) (sql.Result, error)
+	UpdateItem(param sqlc.UpdateChecklistItemParams) (sql.Result, error)
+	ReorderItems(taskID string, itemIDs []int64) error
+	DeleteItem(param sqlc.DeleteChecklistItemParams) (sql.Result, error)
+}
+
+type taskChecklistRepository struct {
+	db   *sqlc.Queries
+	conn *sql.DB
+}
+
+func NewTaskChecklistRepository(conn *sql.DB) TaskChecklistRepository {
+	return &taskChecklistRepository{db: sqlc.New(conn), conn: conn}
+}
+
+func (r *taskChecklistRepository) GetItemsByTaskID(taskID string) ([]sqlc.TaskChecklistItem, error) {
+	const op _error.Op = "repo/GetChecklistItemsByTaskID"
+	result, err := r.db.GetChecklistItemsByTaskID(context.Background(), taskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []sqlc.TaskChecklistItem{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskChecklistRepository) GetItemByID(param sqlc.GetChecklistItemByIDParams) (*sqlc.TaskChecklistItem, error) {
+	const op _error.Op = "repo/GetChecklistItemByID"
+	result, err := r.db.GetChecklistItemByID(context.Background(), param)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, _error.E(
+				op, _error.NotExist, _error.Title("Checklist item not found"),
+				fmt.Sprintf("The requested checklist item with id %d could not be found", param.ID),
+			)
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return &result, nil
+}
+
+func (r *taskChecklistRepository) GetMaxPosition(taskID string) (int64, error) {
+	const op _error.Op = "repo/GetMaxChecklistPosition"
+	result, err := r.db.GetMaxChecklistPosition(context.Background(), taskID)
+	if err != nil {
+		return -1, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskChecklistRepository) GetProgressByTaskIDs(taskIDs []string) ([]sqlc.GetChecklistProgressByTaskIDsRow, error) {
+	const op _error.Op = "repo/GetChecklistProgressByTaskIDs"
+	result, err := r.db.GetChecklistProgressByTaskIDs(context.Background(), taskIDs)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []sqlc.GetChecklistProgressByTaskIDsRow{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskChecklistRepository) CreateItem(param sqlc.CreateChecklistItemParams) (sql.Result, error) {
+	const op _error.Op = "repo/CreateChecklistItem"
+	result, err := r.db.CreateChecklistItem(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskChecklistRepository) UpdateItem(param sqlc.UpdateChecklistItemParams) (sql.Result, error) {
+	const op _error.Op = "repo/UpdateChecklistItem"
+	result, err := r.db.UpdateChecklistItem(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+// ReorderItems moves the items of a task to the positions of their IDs in
+// itemIDs, all in one transaction so a failure leaves the old order intact.
+func (r *taskChecklistRepository) ReorderItems(taskID string, itemIDs []int64) error {
+	const op _error.Op = "repo/ReorderChecklistItems"
+	ctx := context.Background()
+	err := inTx(r.conn, func(q *sqlc.Queries) error {
+		for pos, id := range itemIDs {
+			if _, err := q.UpdateChecklistItemPosition(ctx, sqlc.UpdateChecklistItemPositionParams{
+				Position: int32(pos + 1),
+				ID:       id,
+				TaskID:   taskID,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return _error.E(op, _error.Database, err)
+	}
+	return nil
+}
+
+func (r *taskChecklistRepository) DeleteItem(param sqlc.DeleteChecklistItemParams) (sql.Result, error) {
+	const op _error.Op = "repo/DeleteChecklistItem"
+	result, err := r.db.DeleteChecklistItem(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	if affected == 0 {
+		return nil, _error.E(
+			op, _error.NotExist, _error.Title("No row affected"),
+			fmt.Sprintf("The requested checklist item with id %d could not be found", param.ID),
+		)
+	}
+	return result, nil
+}
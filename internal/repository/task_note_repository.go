@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	_error "courseworker/pkg/error"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type TaskNoteRepository interface {
+	GetNotesByTaskID(param sqlc.GetNotesByTaskIDParams) ([]sqlc.TaskNote, error)
+	GetAllNotesByTaskID(taskID string) ([]sqlc.TaskNote, error)
+	CountNotesByTaskID(taskID string) (int64, error)
+	GetNoteByID(param sqlc.GetNoteByIDParams) (*sqlc.TaskNote, error)
+	CreateNote(param sqlc.CreateNoteParams) (sql.Result, error)
+	UpdateNote(param sqlc.UpdateNoteParams) (sql.Result, error)
+	DeleteNote(param sqlc.DeleteNoteParams) (sql.Result, error)
+}
+
+type taskNoteRepository struct {
+	db *sqlc.Queries
+}
+
+func NewTaskNoteRepository(db *sqlc.Queries) TaskNoteRepository {
+	return &taskNoteRepository{db}
+}
+
+func (r *taskNoteRepository) GetNotesByTaskID(param sqlc.GetNotesByTaskIDParams) ([]sqlc.TaskNote, error) {
+	const op _error.Op = "repo/GetNotesByTaskID"
+	result, err := r.db.GetNotesByTaskID(context.Background(), param)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []sqlc.TaskNote{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskNoteRepository) GetAllNotesByTaskID(taskID string) ([]sqlc.TaskNote, error) {
+	const op _error.Op = "repo/GetAllNotesByTaskID"
+	result, err := r.db.GetAllNotesByTaskID(context.Background(), taskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []sqlc.TaskNote{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskNoteRepository) CountNotesByTaskID(taskID string) (int64, error) {
+	const op _error.Op = "repo/CountNotesByTaskID"
+	result, err := r.db.CountNotesByTaskID(context.Background(), taskID)
+	if err != nil {
+		return -1, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskNoteRepository) GetNoteByID(param sqlc.GetNoteByIDParams) (*sqlc.TaskNote, error) {
+	const op _error.Op = "repo/GetNoteByID"
+	result, err := r.db.GetNoteByID(context.Background(), param)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, _error.E(
+				op, _error.NotExist, _error.Title("Note not found"),
+				fmt.Sprintf("The requested note with id %d could not be found", param.ID),
+			)
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return &result, nil
+}
+
+func (r *taskNoteRepository) CreateNote(param sqlc.CreateNoteParams) (sql.Result, error) {
+	const op _error.Op = "repo/CreateNote"
+	result, err := r.db.CreateNote(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskNoteRepository) UpdateNote(param sqlc.UpdateNoteParams) (sql.Result, error) {
+	const op _error.Op = "repo/UpdateNote"
+	result, err := r.db.UpdateNote(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskNoteRepository) DeleteNote(param sqlc.DeleteNoteParams) (sql.Result, error) {
+	const op _error.Op = "repo/DeleteNote"
+	result, err := r.db.DeleteNote(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	if affected == 0 {
+		return nil, _error.E(
+			op, _error.NotExist, _error.Title("No row affected"),
+			fmt.Sprintf("The requested note with id %d could not be found", param.ID),
+		)
+	}
+	return result, nil
+}
@@ -7,24 +7,33 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 )
 
 type TaskRepository interface {
 	GetAllTasks(userID string) ([]sqlc.Task, error)
 	GetTasksByCourse(courseID int64) ([]sqlc.Task, error)
+	ListTasks(param ListTasksParams) ([]sqlc.Task, error)
 	GetTaskByID(taskID string) (*sqlc.Task, error)
+	GetTasksBySeriesID(seriesID string) ([]sqlc.Task, error)
 	GetUserIDFromTask(param sqlc.GetUserIDFromTaskParams) (string, error)
 	CreateTask(param sqlc.CreateTaskParams) (sql.Result, error)
-	DeleteTask(taskID string) (sql.Result, error)
+	UpdateTask(param sqlc.UpdateTaskParams) (sql.Result, error)
+	DeleteTasks(taskIDs []string, seriesID sql.NullString) error
 	UpdateTaskHighlight(param sqlc.SwitchTaskHighlightParams) (sql.Result, error)
+	SetTaskDone(param sqlc.SetTaskDoneParams) (sql.Result, error)
+	AddImage(param sqlc.AddImageParams) (sql.Result, error)
+	RemoveImage(taskID string) (sql.Result, error)
+	CreateTaskSeries(series sqlc.CreateTaskSeriesParams, tasks []sqlc.CreateTaskParams) error
 }
 
 type taskRepository struct {
-	db *sqlc.Queries
+	db   *sqlc.Queries
+	conn *sql.DB
 }
 
-func NewTaskRepository(db *sqlc.Queries) TaskRepository {
-	return &taskRepository{db}
+func NewTaskRepository(conn *sql.DB) TaskRepository {
+	return &taskRepository{db: sqlc.New(conn), conn: conn}
 }
 
 func (r *taskRepository) GetAllTasks(userID string) ([]sqlc.Task, error) {
@@ -51,6 +60,136 @@ func (r *taskRepository) GetTasksByCourse(courseID int64) ([]sqlc.Task, error) {
 	return result, nil
 }
 
+// Sort columns accepted by ListTasks.
+const (
+	TaskSortDeadline  = "deadline"
+	TaskSortCreatedAt = "created_at"
+	TaskSortTitle     = "title"
+)
+
+var taskSortExprs = map[string]string{
+	TaskSortDeadline:  "COALESCE(t.deadline, TIMESTAMP('2038-01-19 03:14:07'))",
+	TaskSortCreatedAt: "t.created_at",
+	TaskSortTitle:     "t.title",
+}
+
+// TaskListCursor points at the last row of the previous page.
+type TaskListCursor struct {
+	Value interface{}
+	ID    string
+}
+
+// ListTasksParams filters the tasks of UserID. Status is one of "open",
+// "done" or "overdue", where open tasks are neither done nor past their
+// deadline. Zero values leave the corresponding filter out.
+type ListTasksParams struct {
+	UserID       string
+	CourseID     sql.NullInt64
+	Status       string
+	Type         sql.NullString
+	Highlight    sql.NullBool
+	Title        sql.NullString
+	DeadlineFrom sql.NullTime
+	DeadlineTo   sql.NullTime
+	Sort         string
+	Desc         bool
+	After        *TaskListCursor
+	Limit        int32
+}
+
+// ListTasks cannot be generated by sqlc because of its optional filters and
+// caller-chosen sort column, so the query is assembled from whitelisted
+// fragments and run on the connection directly.
+func (r *taskRepository) ListTasks(param ListTasksParams) ([]sqlc.Task, error) {
+	const op _error.Op = "repo/ListTasks"
+	sortExpr, ok := taskSortExprs[param.Sort]
+	if !ok {
+		sortExpr = taskSortExprs[TaskSortDeadline]
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`SELECT t.id, t.course_id, t.is_done, t.title, t.description, t.image, t.type, t.deadline, t.created_at, t.updated_at, t.highlight, t.completed_at, t.series_id FROM tasks t
+INNER JOIN courses c ON t.course_id = c.id
+WHERE c.user_id = ?`)
+	args := []interface{}{param.UserID}
+
+	if param.CourseID.Valid {
+		sb.WriteString(" AND t.course_id = ?")
+		args = append(args, param.CourseID.Int64)
+	}
+	switch param.Status {
+	case "done":
+		sb.WriteString(" AND t.is_done = TRUE")
+	case "overdue":
+		sb.WriteString(" AND t.is_done = FALSE AND t.deadline < NOW()")
+	case "open":
+		sb.WriteString(" AND t.is_done = FALSE AND (t.deadline IS NULL OR t.deadline >= NOW())")
+	}
+	if param.Type.Valid {
+		sb.WriteString(" AND t.type = ?")
+		args = append(args, param.Type.String)
+	}
+	if param.Highlight.Valid {
+		sb.WriteString(" AND t.highlight = ?")
+		args = append(args, param.Highlight.Bool)
+	}
+	if param.Title.Valid {
+		sb.WriteString(" AND t.title LIKE ?")
+		args = append(args, "%"+escapeLike(param.Title.String)+"%")
+	}
+	if param.DeadlineFrom.Valid {
+		sb.WriteString(" AND t.deadline >= ?")
+		args = append(args, param.DeadlineFrom.Time)
+	}
+	if param.DeadlineTo.Valid {
+		sb.WriteString(" AND t.deadline <= ?")
+		args = append(args, param.DeadlineTo.Time)
+	}
+
+	cmp, dir := ">", "ASC"
+	if param.Desc {
+		cmp, dir = "<", "DESC"
+	}
+	if param.After != nil {
+		sb.WriteString(" AND (" + sortExpr + " " + cmp + " ? OR (" + sortExpr + " = ? AND t.id " + cmp + " ?))")
+		args = append(args, param.After.Value, param.After.Value, param.After.ID)
+	}
+	sb.WriteString("\nORDER BY " + sortExpr + " " + dir + ", t.id " + dir + "\nLIMIT ?")
+	args = append(args, param.Limit)
+
+	rows, err := r.conn.QueryContext(context.Background(), sb.String(), args...)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	defer rows.Close()
+	result := []sqlc.Task{}
+	for rows.Next() {
+		var i sqlc.Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.CourseID,
+			&i.IsDone,
+			&i.Title,
+			&i.Description,
+			&i.Image,
+			&i.Type,
+			&i.Deadline,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Highlight,
+			&i.CompletedAt,
+			&i.SeriesID,
+		); err != nil {
+			return nil, _error.E(op, _error.Database, err)
+		}
+		result = append(result, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
 func (r *taskRepository) GetTaskByID(taskID string) (*sqlc.Task, error) {
 	const op _error.Op = "repo/GetTaskByID"
 	result, err := r.db.GetTaskByID(context.Background(), taskID)
@@ -60,6 +199,18 @@ func (r *taskRepository) GetTaskByID(taskID string) (*sqlc.Task, error) {
 	return &result, nil
 }
 
+func (r *taskRepository) GetTasksBySeriesID(seriesID string) ([]sqlc.Task, error) {
+	const op _error.Op = "repo/GetTasksBySeriesID"
+	result, err := r.db.GetTasksBySeriesID(context.Background(), sql.NullString{String: seriesID, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []sqlc.Task{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
 func (r *taskRepository) GetUserIDFromTask(param sqlc.GetUserIDFromTaskParams) (string, error) {
 	const op _error.Op = "repo/GetUserIDFromTask"
 	result, err := r.db.GetUserIDFromTask(context.Background(), param)
@@ -84,23 +235,53 @@ func (r *taskRepository) CreateTask(param sqlc.CreateTaskParams) (sql.Result, er
 	return result, nil
 }
 
-func (r *taskRepository) DeleteTask(taskID string) (sql.Result, error) {
-	const op _error.Op = "repo/DeleteTask"
-	result, err := r.db.DeleteTask(context.Background(), taskID)
+func (r *taskRepository) UpdateTask(param sqlc.UpdateTaskParams) (sql.Result, error) {
+	const op _error.Op = "repo/UpdateTask"
+	result, err := r.db.UpdateTask(context.Background(), param)
 	if err != nil {
 		return nil, _error.E(op, _error.Database, err)
 	}
-	affected, err := result.RowsAffected()
-	if err != nil {
-		return nil, _error.E(op, _error.Database, err)
-	}
-	if affected == 0 {
-		return nil, _error.E(
-			op, _error.Title("No row affected"),
-			fmt.Sprintf("The requested task with id %s could not be found", taskID),
+	return result, nil
+}
+
+// DeleteTasks deletes the tasks, and their series once it has no task left,
+// in one transaction so a failure never leaves a series half deleted.
+func (r *taskRepository) DeleteTasks(taskIDs []string, seriesID sql.NullString) error {
+	const op _error.Op = "repo/DeleteTasks"
+	ctx := context.Background()
+	var missing string
+	err := inTx(r.conn, func(q *sqlc.Queries) error {
+		for _, id := range taskIDs {
+			result, err := q.DeleteTask(ctx, id)
+			if err != nil {
+				return err
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if affected == 0 {
+				missing = id
+				return sql.ErrNoRows
+			}
+		}
+		if seriesID.Valid {
+			if _, err := q.DeleteEmptyTaskSeries(ctx, seriesID.String); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if missing != "" {
+		return _error.E(
+			op, _error.NotExist, _error.Title("No row affected"),
+			fmt.Sprintf("The requested task with id %s could not be found", missing),
 		)
 	}
-	return result, nil
+	if err != nil {
+		return _error.E(op, _error.Database, err)
+	}
+	return nil
 }
 
 func (r *taskRepository) UpdateTaskHighlight(param sqlc.SwitchTaskHighlightParams) (sql.Result, error) {
@@ -122,3 +303,58 @@ func (r *taskRepository) UpdateTaskHighlight(param sqlc.SwitchTaskHighlightParam
 	}
 	return result, nil
 }
+
+func (r *taskRepository) SetTaskDone(param sqlc.SetTaskDoneParams) (sql.Result, error) {
+	const op _error.Op = "repo/SetTaskDone"
+	result, err := r.db.SetTaskDone(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskRepository) AddImage(param sqlc.AddImageParams) (sql.Result, error) {
+	const op _error.Op = "repo/AddImage"
+	result, err := r.db.AddImage(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskRepository) RemoveImage(taskID string) (sql.Result, error) {
+	const op _error.Op = "repo/RemoveImage"
+	result, err := r.db.RemoveImage(context.Background(), taskID)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+// CreateTaskSeries creates a series together with its occurrences in one
+// transaction, so a failure never leaves a partial series behind.
+func (r *taskRepository) CreateTaskSeries(series sqlc.CreateTaskSeriesParams, tasks []sqlc.CreateTaskParams) error {
+	const op _error.Op = "repo/CreateTaskSeries"
+	ctx := context.Background()
+	err := inTx(r.conn, func(q *sqlc.Queries) error {
+		if _, err := q.CreateTaskSeries(ctx, series); err != nil {
+			return err
+		}
+		for _, t := range tasks {
+			if _, err := q.CreateTask(ctx, t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return _error.E(op, _error.Database, err)
+	}
+	return nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern, so a search matches
+// them literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
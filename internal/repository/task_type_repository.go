@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	_error "courseworker/pkg/error"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+type TaskTypeRepository interface {
+	GetTaskTypesByUser(userID string) ([]sqlc.TaskType, error)
+	GetTaskTypeByID(param sqlc.GetTaskTypeByIDParams) (*sqlc.TaskType, error)
+	GetTaskTypeByName(param sqlc.GetTaskTypeByNameParams) (*sqlc.TaskType, error)
+	CreateTaskType(param sqlc.CreateTaskTypeParams) (sql.Result, error)
+	UpdateTaskType(param sqlc.UpdateTaskTypeParams) (sql.Result, error)
+	DeleteTaskType(param sqlc.DeleteTaskTypeParams) (sql.Result, error)
+	CountTasksOfType(param sqlc.CountTasksOfTypeParams) (int64, error)
+	RenameTypeOfTasks(param sqlc.RenameTypeOfTasksParams) (sql.Result, error)
+}
+
+type taskTypeRepository struct {
+	db *sqlc.Queries
+}
+
+func NewTaskTypeRepository(db *sqlc.Queries) TaskTypeRepository {
+	return &taskTypeRepository{db}
+}
+
+func (r *taskTypeRepository) GetTaskTypesByUser(userID string) ([]sqlc.TaskType, error) {
+	const op _error.Op = "repo/GetTaskTypesByUser"
+	result, err := r.db.GetTaskTypesByUser(context.Background(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []sqlc.TaskType{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskTypeRepository) GetTaskTypeByID(param sqlc.GetTaskTypeByIDParams) (*sqlc.TaskType, error) {
+	const op _error.Op = "repo/GetTaskTypeByID"
+	result, err := r.db.GetTaskTypeByID(context.Background(), param)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, _error.E(
+				op, _error.NotExist, _error.Title("Task type not found"),
+				fmt.Sprintf("The requested task type with id %d could not be found", param.ID),
+			)
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return &result, nil
+}
+
+func (r *taskTypeRepository) GetTaskTypeByName(param sqlc.GetTaskTypeByNameParams) (*sqlc.TaskType, error) {
+	const op _error.Op = "repo/GetTaskTypeByName"
+	result, err := r.db.GetTaskTypeByName(context.Background(), param)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, _error.E(
+				op, _error.NotExist, _error.Title("Task type not found"),
+				fmt.Sprintf("The task type %q does not exist", param.Name),
+			)
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return &result, nil
+}
+
+func (r *taskTypeRepository) CreateTaskType(param sqlc.CreateTaskTypeParams) (sql.Result, error) {
+	const op _error.Op = "repo/CreateTaskType"
+	result, err := r.db.CreateTaskType(context.Background(), param)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return nil, _error.E(op, _error.Exist, fmt.Sprintf("The task type %q already exists", param.Name))
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskTypeRepository) UpdateTaskType(param sqlc.UpdateTaskTypeParams) (sql.Result, error) {
+	const op _error.Op = "repo/UpdateTaskType"
+	result, err := r.db.UpdateTaskType(context.Background(), param)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return nil, _error.E(op, _error.Exist, fmt.Sprintf("The task type %q already exists", param.Name))
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskTypeRepository) DeleteTaskType(param sqlc.DeleteTaskTypeParams) (sql.Result, error) {
+	const op _error.Op = "repo/DeleteTaskType"
+	result, err := r.db.DeleteTaskType(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	if affected == 0 {
+		return nil, _error.E(
+			op, _error.NotExist, _error.Title("No row affected"),
+			fmt.Sprintf("The requested task type with id %d could not be found", param.ID),
+		)
+	}
+	return result, nil
+}
+
+func (r *taskTypeRepository) CountTasksOfType(param sqlc.CountTasksOfTypeParams) (int64, error) {
+	const op _error.Op = "repo/CountTasksOfType"
+	result, err := r.db.CountTasksOfType(context.Background(), param)
+	if err != nil {
+		return -1, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *taskTypeRepository) RenameTypeOfTasks(param sqlc.RenameTypeOfTasksParams) (sql.Result, error) {
+	const op _error.Op = "repo/RenameTypeOfTasks"
+	result, err := r.db.RenameTypeOfTasks(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+// isDuplicateEntry reports whether err is a MySQL unique key violation.
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	"database/sql"
+)
+
+// inTx runs fn with queries bound to a transaction on conn. The transaction
+// is committed when fn succeeds and rolled back otherwise.
+func inTx(conn *sql.DB, fn func(q *sqlc.Queries) error) error {
+	tx, err := conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(sqlc.New(tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
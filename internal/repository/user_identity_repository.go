@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	_error "courseworker/pkg/error"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type UserIdentityRepository interface {
+	GetIdentity(param sqlc.GetIdentityParams) (*sqlc.UserIdentity, error)
+	GetIdentitiesByUserID(userID string) ([]sqlc.UserIdentity, error)
+	CreateIdentity(param sqlc.CreateIdentityParams) (sql.Result, error)
+	DeleteIdentity(param sqlc.DeleteIdentityParams) (sql.Result, error)
+}
+
+type userIdentityRepository struct {
+	db *sqlc.Queries
+}
+
+func NewUserIdentityRepository(db *sqlc.Queries) UserIdentityRepository {
+	return &userIdentityRepository{db}
+}
+
+func (r *userIdentityRepository) GetIdentity(param sqlc.GetIdentityParams) (*sqlc.UserIdentity, error) {
+	const op _error.Op = "repo/GetIdentity"
+	result, err := r.db.GetIdentity(context.Background(), param)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, _error.E(
+				op, _error.NotExist, _error.Title("Identity not found"),
+				fmt.Sprintf("No user is linked to this %s account", param.Provider),
+			)
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return &result, nil
+}
+
+func (r *userIdentityRepository) GetIdentitiesByUserID(userID string) ([]sqlc.UserIdentity, error) {
+	const op _error.Op = "repo/GetIdentitiesByUserID"
+	result, err := r.db.GetIdentitiesByUserID(context.Background(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []sqlc.UserIdentity{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *userIdentityRepository) CreateIdentity(param sqlc.CreateIdentityParams) (sql.Result, error) {
+	const op _error.Op = "repo/CreateIdentity"
+	result, err := r.db.CreateIdentity(context.Background(), param)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return nil, _error.E(op, _error.Exist, fmt.Sprintf("A %s account is already linked", param.Provider))
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *userIdentityRepository) DeleteIdentity(param sqlc.DeleteIdentityParams) (sql.Result, error) {
+	const op _error.Op = "repo/DeleteIdentity"
+	result, err := r.db.DeleteIdentity(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	if affected == 0 {
+		return nil, _error.E(
+			op, _error.NotExist, _error.Title("No row affected"),
+			fmt.Sprintf("No %s account is linked", param.Provider),
+		)
+	}
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	_error "courseworker/pkg/error"
+	"database/sql"
+	"errors"
+)
+
+type UserMFARepository interface {
+	GetUserMFA(userID string) (*sqlc.UserMfa, error)
+	UpsertUserMFA(param sqlc.UpsertUserMFAParams) (sql.Result, error)
+	EnableUserMFA(userID string) (sql.Result, error)
+	DeleteUserMFA(userID string) (sql.Result, error)
+	CountUnusedRecoveryCodes(userID string) (int64, error)
+	GetUnusedRecoveryCodes(userID string) ([]sqlc.UserRecoveryCode, error)
+	CreateRecoveryCode(param sqlc.CreateRecoveryCodeParams) (sql.Result, error)
+	UseRecoveryCode(param sqlc.UseRecoveryCodeParams) (bool, error)
+	DeleteRecoveryCodes(userID string) (sql.Result, error)
+}
+
+type userMFARepository struct {
+	db *sqlc.Queries
+}
+
+func NewUserMFARepository(db *sqlc.Queries) UserMFARepository {
+	return &userMFARepository{db}
+}
+
+func (r *userMFARepository) GetUserMFA(userID string) (*sqlc.UserMfa, error) {
+	const op _error.Op = "repo/GetUserMFA"
+	result, err := r.db.GetUserMFA(context.Background(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, _error.E(
+				op, _error.NotExist, _error.Title("Two-factor authentication not found"),
+				"Two-factor authentication has not been set up",
+			)
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return &result, nil
+}
+
+func (r *userMFARepository) UpsertUserMFA(param sqlc.UpsertUserMFAParams) (sql.Result, error) {
+	const op _error.Op = "repo/UpsertUserMFA"
+	result, err := r.db.UpsertUserMFA(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *userMFARepository) EnableUserMFA(userID string) (sql.Result, error) {
+	const op _error.Op = "repo/EnableUserMFA"
+	result, err := r.db.EnableUserMFA(context.Background(), userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *userMFARepository) DeleteUserMFA(userID string) (sql.Result, error) {
+	const op _error.Op = "repo/DeleteUserMFA"
+	result, err := r.db.DeleteUserMFA(context.Background(), userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *userMFARepository) CountUnusedRecoveryCodes(userID string) (int64, error) {
+	const op _error.Op = "repo/CountUnusedRecoveryCodes"
+	result, err := r.db.CountUnusedRecoveryCodes(context.Background(), userID)
+	if err != nil {
+		return -1, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *userMFARepository) GetUnusedRecoveryCodes(userID string) ([]sqlc.UserRecoveryCode, error) {
+	const op _error.Op = "repo/GetUnusedRecoveryCodes"
+	result, err := r.db.GetUnusedRecoveryCodes(context.Background(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []sqlc.UserRecoveryCode{}, nil
+		}
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *userMFARepository) CreateRecoveryCode(param sqlc.CreateRecoveryCodeParams) (sql.Result, error) {
+	const op _error.Op = "repo/CreateRecoveryCode"
+	result, err := r.db.CreateRecoveryCode(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+// UseRecoveryCode marks the code as used and reports whether it was still
+// unused. The conditional update makes concurrent uses of one code safe.
+func (r *userMFARepository) UseRecoveryCode(param sqlc.UseRecoveryCodeParams) (bool, error) {
+	const op _error.Op = "repo/UseRecoveryCode"
+	result, err := r.db.UseRecoveryCode(context.Background(), param)
+	if err != nil {
+		return false, _error.E(op, _error.Database, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, _error.E(op, _error.Database, err)
+	}
+	return affected == 1, nil
+}
+
+func (r *userMFARepository) DeleteRecoveryCodes(userID string) (sql.Result, error) {
+	const op _error.Op = "repo/DeleteRecoveryCodes"
+	result, err := r.db.DeleteRecoveryCodes(context.Background(), userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
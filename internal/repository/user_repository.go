@@ -6,30 +6,116 @@ import (
 	_error "courseworker/pkg/error"
 	"database/sql"
 	"errors"
+	"strings"
+	"time"
 )
 
 type UserRepository interface {
-	GetAllUsers() ([]sqlc.GetAllUsersRow, error)
+	ListUsers(param ListUsersParams) ([]sqlc.User, error)
 	GetUserByID(userID string) (*sqlc.User, error)
 	EmailExists(email string) (int64, error)
 	GetUserByEmail(email string) (*sqlc.User, error)
 	CreateUser(sqlc.CreateUserParams) (sql.Result, error)
+	CreateAccount(param CreateAccountParams) error
+	GetUserTimezone(userID string) (string, error)
+	UpdateUserTimezone(param sqlc.UpdateUserTimezoneParams) (sql.Result, error)
+	UpdateUserPassword(param sqlc.UpdateUserPasswordParams) (sql.Result, error)
+	UpdateUserProfile(param sqlc.UpdateUserProfileParams) (sql.Result, error)
+	UpdateUserEmail(param sqlc.UpdateUserEmailParams) (sql.Result, error)
+	UpdateUserProfileImg(param sqlc.UpdateUserProfileImgParams) (sql.Result, error)
+	UpdateUserRole(param sqlc.UpdateUserRoleParams) (sql.Result, error)
+	UpdateUserRoleByEmails(param sqlc.UpdateUserRoleByEmailsParams) (int64, error)
+	SetUserDisabledAt(param sqlc.SetUserDisabledAtParams) (sql.Result, error)
+	DeleteAccount(userID string) (*DeletedAccount, error)
+	GetUsageStats() (*sqlc.GetUsageStatsRow, error)
 }
 
 type userRepository struct {
-	db *sqlc.Queries
+	db   *sqlc.Queries
+	conn *sql.DB
 }
 
-func NewUserRepository(db *sqlc.Queries) UserRepository {
-	return &userRepository{db}
+func NewUserRepository(conn *sql.DB) UserRepository {
+	return &userRepository{db: sqlc.New(conn), conn: conn}
 }
 
-func (r *userRepository) GetAllUsers() ([]sqlc.GetAllUsersRow, error) {
-	const op _error.Op = "repo/GetAllUsers"
-	result, err := r.db.GetAllUsers(context.Background())
+// UserListCursor points at the last row of the previous page.
+type UserListCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// ListUsersParams filters users by a name or email substring, by role and by
+// whether the account is disabled. Zero values leave the filter out. Users are
+// returned newest first.
+type ListUsersParams struct {
+	Query    sql.NullString
+	Role     sql.NullString
+	Disabled sql.NullBool
+	After    *UserListCursor
+	Limit    int32
+}
+
+// ListUsers assembles the admin user search from its optional filters, which
+// sqlc cannot express, and runs it on the connection directly.
+func (r *userRepository) ListUsers(param ListUsersParams) ([]sqlc.User, error) {
+	const op _error.Op = "repo/ListUsers"
+	var sb strings.Builder
+	sb.WriteString(`SELECT id, name, email, password, profile_img, created_at, updated_at, timezone, role, disabled_at, locale FROM users
+WHERE TRUE`)
+	args := []interface{}{}
+
+	if param.Query.Valid {
+		pattern := "%" + escapeLike(param.Query.String) + "%"
+		sb.WriteString(" AND (name LIKE ? OR email LIKE ?)")
+		args = append(args, pattern, pattern)
+	}
+	if param.Role.Valid {
+		sb.WriteString(" AND role = ?")
+		args = append(args, param.Role.String)
+	}
+	if param.Disabled.Valid {
+		if param.Disabled.Bool {
+			sb.WriteString(" AND disabled_at IS NOT NULL")
+		} else {
+			sb.WriteString(" AND disabled_at IS NULL")
+		}
+	}
+	if param.After != nil {
+		sb.WriteString(" AND (created_at < ? OR (created_at = ? AND id < ?))")
+		args = append(args, param.After.CreatedAt, param.After.CreatedAt, param.After.ID)
+	}
+	sb.WriteString("\nORDER BY created_at DESC, id DESC\nLIMIT ?")
+	args = append(args, param.Limit)
+
+	rows, err := r.conn.QueryContext(context.Background(), sb.String(), args...)
 	if err != nil {
 		return nil, _error.E(op, _error.Database, err)
 	}
+	defer rows.Close()
+	result := []sqlc.User{}
+	for rows.Next() {
+		var i sqlc.User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Password,
+			&i.ProfileImg,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Timezone,
+			&i.Role,
+			&i.DisabledAt,
+			&i.Locale,
+		); err != nil {
+			return nil, _error.E(op, _error.Database, err)
+		}
+		result = append(result, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
 	return result, nil
 }
 
@@ -74,3 +160,196 @@ func (r *userRepository) CreateUser(param sqlc.CreateUserParams) (sql.Result, er
 	}
 	return result, nil
 }
+
+// CreateAccountParams describes a new account and the rows that come with it.
+// ProfileImg and Identity are set for accounts created through a provider.
+type CreateAccountParams struct {
+	User       sqlc.CreateUserParams
+	ProfileImg sql.NullString
+	TaskTypes  []sqlc.CreateTaskTypeParams
+	Identity   *sqlc.CreateIdentityParams
+}
+
+// CreateAccount creates the user together with their task types and linked
+// identity in one transaction, so an account never exists half set up.
+func (r *userRepository) CreateAccount(param CreateAccountParams) error {
+	const op _error.Op = "repo/CreateAccount"
+	ctx := context.Background()
+	err := inTx(r.conn, func(q *sqlc.Queries) error {
+		if _, err := q.CreateUser(ctx, param.User); err != nil {
+			return err
+		}
+		if param.ProfileImg.Valid {
+			if _, err := q.UpdateUserProfileImg(ctx, sqlc.UpdateUserProfileImgParams{
+				ProfileImg: param.ProfileImg,
+				ID:         param.User.ID,
+			}); err != nil {
+				return err
+			}
+		}
+		for _, t := range param.TaskTypes {
+			if _, err := q.CreateTaskType(ctx, t); err != nil {
+				return err
+			}
+		}
+		if param.Identity != nil {
+			if _, err := q.CreateIdentity(ctx, *param.Identity); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return _error.E(op, _error.Exist, "The account already exists")
+		}
+		return _error.E(op, _error.Database, err)
+	}
+	return nil
+}
+
+func (r *userRepository) GetUserTimezone(userID string) (string, error) {
+	const op _error.Op = "repo/GetUserTimezone"
+	result, err := r.db.GetUserTimezone(context.Background(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", _error.E(op, _error.NotExist, err)
+		}
+		return "", _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *userRepository) UpdateUserTimezone(param sqlc.UpdateUserTimezoneParams) (sql.Result, error) {
+	const op _error.Op = "repo/UpdateUserTimezone"
+	result, err := r.db.UpdateUserTimezone(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *userRepository) UpdateUserPassword(param sqlc.UpdateUserPasswordParams) (sql.Result, error) {
+	const op _error.Op = "repo/UpdateUserPassword"
+	result, err := r.db.UpdateUserPassword(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *userRepository) UpdateUserProfile(param sqlc.UpdateUserProfileParams) (sql.Result, error) {
+	const op _error.Op = "repo/UpdateUserProfile"
+	result, err := r.db.UpdateUserProfile(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *userRepository) UpdateUserEmail(param sqlc.UpdateUserEmailParams) (sql.Result, error) {
+	const op _error.Op = "repo/UpdateUserEmail"
+	result, err := r.db.UpdateUserEmail(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *userRepository) UpdateUserProfileImg(param sqlc.UpdateUserProfileImgParams) (sql.Result, error) {
+	const op _error.Op = "repo/UpdateUserProfileImg"
+	result, err := r.db.UpdateUserProfileImg(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+func (r *userRepository) UpdateUserRole(param sqlc.UpdateUserRoleParams) (sql.Result, error) {
+	const op _error.Op = "repo/UpdateUserRole"
+	result, err := r.db.UpdateUserRole(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+// UpdateUserRoleByEmails returns how many users changed role.
+func (r *userRepository) UpdateUserRoleByEmails(param sqlc.UpdateUserRoleByEmailsParams) (int64, error) {
+	const op _error.Op = "repo/UpdateUserRoleByEmails"
+	result, err := r.db.UpdateUserRoleByEmails(context.Background(), param)
+	if err != nil {
+		return -1, _error.E(op, _error.Database, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return -1, _error.E(op, _error.Database, err)
+	}
+	return affected, nil
+}
+
+func (r *userRepository) SetUserDisabledAt(param sqlc.SetUserDisabledAtParams) (sql.Result, error) {
+	const op _error.Op = "repo/SetUserDisabledAt"
+	result, err := r.db.SetUserDisabledAt(context.Background(), param)
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return result, nil
+}
+
+// DeletedAccount lists what went away with an account, so the caches and
+// blobs kept for it outside the database can be cleaned up.
+type DeletedAccount struct {
+	CourseIDs []int64
+	TaskIDs   []string
+}
+
+// DeleteAccount deletes the user and their courses in one transaction. Every
+// other row of the user cascades from these two.
+func (r *userRepository) DeleteAccount(userID string) (*DeletedAccount, error) {
+	const op _error.Op = "repo/DeleteAccount"
+	ctx := context.Background()
+	deleted := &DeletedAccount{}
+	missing := false
+	err := inTx(r.conn, func(q *sqlc.Queries) error {
+		var err error
+		if deleted.CourseIDs, err = q.GetCourseIDsByUser(ctx, userID); err != nil {
+			return err
+		}
+		if deleted.TaskIDs, err = q.GetTaskIDsByUser(ctx, userID); err != nil {
+			return err
+		}
+		if _, err := q.DeleteCoursesByUser(ctx, userID); err != nil {
+			return err
+		}
+		result, err := q.DeleteUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			missing = true
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+	if missing {
+		return nil, _error.E(op, _error.NotExist, "The requested user could not be found")
+	}
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return deleted, nil
+}
+
+func (r *userRepository) GetUsageStats() (*sqlc.GetUsageStatsRow, error) {
+	const op _error.Op = "repo/GetUsageStats"
+	result, err := r.db.GetUsageStats(context.Background())
+	if err != nil {
+		return nil, _error.E(op, _error.Database, err)
+	}
+	return &result, nil
+}
@@ -70,10 +70,9 @@ func main() {
 	defer db.Close()
 	defer rdc.Close()
 
-	queries := sqlc.New(db)
-	repo_t := repository.NewTaskRepository(queries)
-	repo_c := repository.NewCourseRepository(queries)
-	repo_u := repository.NewUserRepository(queries)
+	repo_t := repository.NewTaskRepository(db)
+	repo_c := repository.NewCourseRepository(db)
+	repo_u := repository.NewUserRepository(db)
 
 	fmt.Print("User's email: ")
 	var email string
@@ -113,7 +112,7 @@ func main() {
 				ID:       uuid.New().String(),
 				CourseID: int64(c),
 				Title:    fmt.Sprintf("Task %d of Course%d", i+1, c),
-				Type:     "Individual",
+				Type:     "assignment",
 				Description: sql.NullString{
 					String: "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Proin in tellus ac dui suscipit imperdiet. Aliquam bibendum ipsum mi, vel feugiat nunc lacinia a.",
 					Valid:  true,
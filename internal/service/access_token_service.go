@@ -0,0 +1,159 @@
+package service
+
+import (
+	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
+	"courseworker/internal/repository"
+	_error "courseworker/pkg/error"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// accessTokenTouchInterval throttles last_used_at updates so a busy script
+// does not write to the database on every request. Only the SHA-256 of a
+// token is stored.
+const accessTokenTouchInterval = time.Minute
+
+type AccessTokenService interface {
+	GetTokens(c *gin.Context, userID string) ([]dto.AccessTokenResponse, error)
+	CreateToken(c *gin.Context, userID string, req dto.AccessTokenReq) (*dto.AccessTokenCreatedResponse, error)
+	DeleteToken(c *gin.Context, userID, tokenID string) error
+	Authenticate(c *gin.Context, token string) (*dto.UserClaims, error)
+}
+
+type accessTokenService struct {
+	repo     repository.PersonalAccessTokenRepository
+	userRepo repository.UserRepository
+}
+
+func NewAccessTokenService(r repository.PersonalAccessTokenRepository, ur repository.UserRepository) AccessTokenService {
+	return &accessTokenService{
+		repo:     r,
+		userRepo: ur,
+	}
+}
+
+func (s *accessTokenService) GetTokens(c *gin.Context, userID string) ([]dto.AccessTokenResponse, error) {
+	const op _error.Op = "serv/GetAccessTokens"
+
+	tokens, err := s.repo.GetPersonalAccessTokensByUserID(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get access tokens"), err)
+	}
+	return dto.ToAccessTokenResponses(&tokens), nil
+}
+
+func (s *accessTokenService) CreateToken(c *gin.Context, userID string, req dto.AccessTokenReq) (*dto.AccessTokenCreatedResponse, error) {
+	const op _error.Op = "serv/CreateAccessToken"
+
+	secret, err := generateSecretToken()
+	if err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to create access token"), err)
+	}
+	token := dto.AccessTokenPrefix + secret
+
+	param := sqlc.CreatePersonalAccessTokenParams{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		TokenHash: hashSecretToken(token),
+		Scopes:    strings.Join(uniqueScopes(req.Scopes), " "),
+	}
+	if req.ExpiresInDays > 0 {
+		param.ExpiresAt = sql.NullTime{
+			Time:  time.Now().UTC().AddDate(0, 0, req.ExpiresInDays).Truncate(time.Second),
+			Valid: true,
+		}
+	}
+	if _, err := s.repo.CreatePersonalAccessToken(param); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to create access token"), err)
+	}
+
+	created := sqlc.PersonalAccessToken{
+		ID:        param.ID,
+		UserID:    param.UserID,
+		Name:      param.Name,
+		Scopes:    param.Scopes,
+		ExpiresAt: param.ExpiresAt,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	return &dto.AccessTokenCreatedResponse{
+		AccessTokenResponse: dto.ToAccessTokenResponse(&created),
+		Token:               token,
+	}, nil
+}
+
+func (s *accessTokenService) DeleteToken(c *gin.Context, userID, tokenID string) error {
+	const op _error.Op = "serv/DeleteAccessToken"
+
+	if _, err := s.repo.DeletePersonalAccessToken(sqlc.DeletePersonalAccessTokenParams{
+		ID:     tokenID,
+		UserID: userID,
+	}); err != nil {
+		return _error.E(op, _error.Title("Failed to delete access token"), err)
+	}
+	return nil
+}
+
+// Authenticate resolves a personal access token to the claims of its owner,
+// restricted to the token's scopes. Tokens of disabled accounts are rejected
+// like their sessions are.
+func (s *accessTokenService) Authenticate(c *gin.Context, token string) (*dto.UserClaims, error) {
+	const op _error.Op = "serv/AuthenticateAccessToken"
+
+	if !strings.HasPrefix(token, dto.AccessTokenPrefix) {
+		return nil, _error.E(op, _error.Unauthorized, _error.Title("Invalid token"), "not a personal access token")
+	}
+	pat, err := s.repo.GetPersonalAccessTokenByHash(hashSecretToken(token))
+	if err != nil {
+		if isKind(err, _error.NotExist) {
+			return nil, _error.E(op, _error.Unauthorized, _error.Title("Invalid token"), "access token does not exist")
+		}
+		return nil, _error.E(op, err)
+	}
+	if pat.ExpiresAt.Valid && time.Now().After(pat.ExpiresAt.Time) {
+		return nil, _error.E(op, _error.Unauthorized, _error.Title("Invalid token"), "access token has expired")
+	}
+
+	user, err := s.userRepo.GetUserByID(pat.UserID)
+	if err != nil {
+		if isKind(err, _error.NotExist) {
+			return nil, _error.E(op, _error.Unauthorized, _error.Title("Invalid token"), "access token owner does not exist")
+		}
+		return nil, _error.E(op, err)
+	}
+	if user.DisabledAt.Valid {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Account disabled"), "this account has been disabled")
+	}
+
+	if !pat.LastUsedAt.Valid || time.Since(pat.LastUsedAt.Time) > accessTokenTouchInterval {
+		if _, err := s.repo.TouchPersonalAccessToken(pat.ID); err != nil {
+			log.Printf("Failed to update access token %s last use: %v", pat.ID, err)
+		}
+	}
+
+	// Personal access tokens never carry the role, so scripts cannot use the
+	// admin routes nor the admin views of shared ones.
+	return &dto.UserClaims{
+		ID:      user.ID,
+		TokenID: pat.ID,
+		Scopes:  strings.Fields(pat.Scopes),
+	}, nil
+}
+
+func uniqueScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	unique := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+	return unique
+}
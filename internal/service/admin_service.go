@@ -0,0 +1,178 @@
+package service
+
+import (
+	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
+	"courseworker/internal/repository"
+	_error "courseworker/pkg/error"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+type AdminService interface {
+	DisableUser(c *gin.Context, adminID, userID string) (*dto.UserResponse, error)
+	EnableUser(c *gin.Context, userID string) (*dto.UserResponse, error)
+	UpdateUserRole(c *gin.Context, adminID, userID string, req dto.UserRoleReq) (*dto.UserResponse, error)
+	ForceLogout(c *gin.Context, userID string) error
+	GetUsageStats(c *gin.Context) (*dto.UsageStatsResponse, error)
+	PromoteAdmins(emails []string) error
+}
+
+type adminService struct {
+	repo repository.UserRepository
+	rd   *redis.Client
+	us   UserService
+	ss   SessionService
+}
+
+func NewAdminService(r repository.UserRepository, rdc *redis.Client, userServ UserService, sessionServ SessionService) AdminService {
+	return &adminService{
+		repo: r,
+		rd:   rdc,
+		us:   userServ,
+		ss:   sessionServ,
+	}
+}
+
+// DisableUser blocks the account from logging in and ends all of its
+// sessions.
+func (s *adminService) DisableUser(c *gin.Context, adminID, userID string) (*dto.UserResponse, error) {
+	const op _error.Op = "serv/DisableUser"
+
+	if adminID == userID {
+		return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to disable user"), "you cannot disable your own account")
+	}
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get user"), err)
+	}
+
+	if !user.DisabledAt.Valid {
+		if _, err := s.repo.SetUserDisabledAt(sqlc.SetUserDisabledAtParams{
+			DisabledAt: sql.NullTime{Time: time.Now(), Valid: true},
+			ID:         userID,
+		}); err != nil {
+			return nil, _error.E(op, _error.Title("Failed to disable user"), err)
+		}
+	}
+	if err := s.ss.RevokeAll(c, userID); err != nil {
+		return nil, _error.E(op, err)
+	}
+
+	return s.us.GetUserByID(c, userID)
+}
+
+func (s *adminService) EnableUser(c *gin.Context, userID string) (*dto.UserResponse, error) {
+	const op _error.Op = "serv/EnableUser"
+
+	if _, err := s.repo.GetUserByID(userID); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get user"), err)
+	}
+	if _, err := s.repo.SetUserDisabledAt(sqlc.SetUserDisabledAtParams{ID: userID}); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to enable user"), err)
+	}
+
+	return s.us.GetUserByID(c, userID)
+}
+
+// UpdateUserRole changes the user's role. The role is carried in access
+// tokens, so the user's existing tokens are revoked to pick up the change.
+func (s *adminService) UpdateUserRole(c *gin.Context, adminID, userID string, req dto.UserRoleReq) (*dto.UserResponse, error) {
+	const op _error.Op = "serv/UpdateUserRole"
+
+	if adminID == userID {
+		return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to update role"), "you cannot change your own role")
+	}
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get user"), err)
+	}
+
+	if user.Role != req.Role {
+		if _, err := s.repo.UpdateUserRole(sqlc.UpdateUserRoleParams{
+			Role: req.Role,
+			ID:   userID,
+		}); err != nil {
+			return nil, _error.E(op, _error.Title("Failed to update role"), err)
+		}
+		if err := s.ss.RevokeAll(c, userID); err != nil {
+			return nil, _error.E(op, err)
+		}
+	}
+
+	return s.us.GetUserByID(c, userID)
+}
+
+// PromoteAdmins gives the accounts with the given emails the admin role. It
+// runs at startup with ADMIN_EMAILS, so a new deployment gets its first admin
+// without touching the database.
+func (s *adminService) PromoteAdmins(emails []string) error {
+	const op _error.Op = "serv/PromoteAdmins"
+
+	var valid []string
+	for _, e := range emails {
+		if e = strings.TrimSpace(e); e != "" {
+			valid = append(valid, e)
+		}
+	}
+	if len(valid) == 0 {
+		return nil
+	}
+
+	promoted, err := s.repo.UpdateUserRoleByEmails(sqlc.UpdateUserRoleByEmailsParams{
+		Role:   dto.RoleAdmin,
+		Emails: valid,
+	})
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to promote admins"), err)
+	}
+	if promoted > 0 {
+		log.Printf("Granted the admin role to %d account(s) from ADMIN_EMAILS", promoted)
+	}
+	return nil
+}
+
+func (s *adminService) ForceLogout(c *gin.Context, userID string) error {
+	const op _error.Op = "serv/ForceLogout"
+
+	if _, err := s.repo.GetUserByID(userID); err != nil {
+		return _error.E(op, _error.Title("Failed to get user"), err)
+	}
+	if err := s.ss.RevokeAll(c, userID); err != nil {
+		return _error.E(op, err)
+	}
+	return nil
+}
+
+func (s *adminService) GetUsageStats(c *gin.Context) (*dto.UsageStatsResponse, error) {
+	const op _error.Op = "serv/GetUsageStats"
+
+	stats, err := s.repo.GetUsageStats()
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get usage stats"), err)
+	}
+
+	resp := dto.ToUsageStatsResponse(stats)
+	resp.ActiveSessions = s.countSessions(c)
+	return resp, nil
+}
+
+// countSessions counts the live session hashes. Sessions only exist in Redis,
+// so a failed scan is logged and reported as zero.
+func (s *adminService) countSessions(c *gin.Context) int64 {
+	var count int64
+	iter := s.rd.Scan(c, 0, sessionKeyPrefix+"*", 1000).Iterator()
+	for iter.Next(c) {
+		count++
+	}
+	if err := iter.Err(); err != nil {
+		log.Printf("Redis Scan failed: %v", err)
+		return 0
+	}
+	return count
+}
@@ -1,37 +1,64 @@
 package service
 
 import (
+	"bytes"
+	"context"
 	"courseworker/internal/db/sqlc"
 	"courseworker/internal/dto"
 	"courseworker/internal/repository"
 	_error "courseworker/pkg/error"
+	"courseworker/pkg/jobs"
+	"courseworker/pkg/storage"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
+// ExportCourseJob writes a course with its tasks to a JSON blob and tells the
+// user through an export_ready notification once it can be downloaded.
+var ExportCourseJob = jobs.Type[dto.CourseExportJob]{Name: "course.export"}
+
+// exportURLTTL bounds the download links handed out for exports. The export
+// itself stays until the course is deleted, so a new link can be fetched.
+const exportURLTTL = 15 * time.Minute
+
 type CourseService interface {
 	GetCoursesOfUser(userID string) ([]dto.CourseResponse, error)
 	GetCourseByID(c *gin.Context, userID string, courseID int64) (*dto.CourseResponse, error)
 	CreateCourse(c *gin.Context, userID string, arg dto.CourseCreateUpdateReq) (*dto.ResponseID, error)
 	UpdateCourse(c *gin.Context, userID string, courseID int64, arg dto.CourseCreateUpdateReq) (*dto.ResponseID, error)
 	DeleteCourse(c *gin.Context, userID string, courseID int64) error
+	ExportCourse(c *gin.Context, userID string, courseID int64) (*dto.CourseExportResponse, error)
+	GetExport(c *gin.Context, userID string, courseID int64, exportID string) (*dto.CourseExportResponse, error)
+	RunExport(ctx context.Context, job dto.CourseExportJob) error
 	ValidateOwnershipCourse(c *gin.Context, authUserID string, courseID int64) error
 }
 
 type courseService struct {
-	repo repository.CourseRepository
-	rd   *redis.Client
+	repo       repository.CourseRepository
+	attachRepo repository.TaskAttachmentRepository
+	rd         *redis.Client
+	blob       storage.Blob
+	queue      *jobs.Queue
+	ns         NotificationPublisher
 }
 
-func NewCourseService(r repository.CourseRepository, rdc *redis.Client) CourseService {
+func NewCourseService(r repository.CourseRepository, ar repository.TaskAttachmentRepository, rdc *redis.Client, blob storage.Blob, queue *jobs.Queue, notifications NotificationPublisher) CourseService {
 	return &courseService{
-		repo: r,
-		rd:   rdc,
+		repo:       r,
+		attachRepo: ar,
+		rd:         rdc,
+		blob:       blob,
+		queue:      queue,
+		ns:         notifications,
 	}
 }
 
@@ -139,15 +166,134 @@ func (s *courseService) DeleteCourse(c *gin.Context, userID string, courseID int
 		return _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
 	}
 
-	_, err := s.repo.DeleteCourse(courseID)
+	taskIDs, err := s.repo.GetTaskIDsByCourse(courseID)
 	if err != nil {
 		return _error.E(op, _error.Title("Failed to delete course"), err)
 	}
+	blobKeys, err := s.attachRepo.GetAttachmentBlobKeysByCourse(courseID)
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to delete course"), err)
+	}
+
+	if err := s.repo.DeleteCourse(courseID); err != nil {
+		return _error.E(op, _error.Title("Failed to delete course"), err)
+	}
 
 	key := "course:" + strconv.Itoa(int(courseID))
 	if err := s.rd.Del(c, key).Err(); err != nil {
 		log.Printf("Redis Delete failed: %v", err)
 	}
 
+	for _, taskID := range taskIDs {
+		if err := s.rd.Del(c, "task:"+taskID).Err(); err != nil {
+			log.Printf("Redis Delete failed: %v", err)
+		}
+		if err := s.blob.DeletePrefix(c, taskBlobPrefix(taskID)); err != nil {
+			log.Printf("Blob cleanup failed for task %s: %v", taskID, err)
+		}
+	}
+	removeUnreferencedBlobs(c, s.rd, s.attachRepo, s.blob, blobKeys)
+	if err := s.blob.DeletePrefix(c, courseExportPrefix(userID, courseID)); err != nil {
+		log.Printf("Blob cleanup failed for exports of course %d: %v", courseID, err)
+	}
+
 	return nil
 }
+
+// ExportCourse queues an export of the course. The user is notified once the
+// export is ready, and downloads it through GetExport with the returned ID.
+func (s *courseService) ExportCourse(c *gin.Context, userID string, courseID int64) (*dto.CourseExportResponse, error) {
+	const op _error.Op = "serv/ExportCourse"
+
+	if err := s.ValidateOwnershipCourse(c, userID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
+	}
+
+	exportID := uuid.New().String()
+	if err := ExportCourseJob.Enqueue(c, s.queue, dto.CourseExportJob{
+		ExportID: exportID,
+		UserID:   userID,
+		CourseID: courseID,
+	}); err != nil {
+		return nil, _error.E(op, _error.Cache, _error.Title("Failed to export course"), err)
+	}
+	return &dto.CourseExportResponse{ID: exportID}, nil
+}
+
+// GetExport returns a short-lived download link for a finished export.
+func (s *courseService) GetExport(c *gin.Context, userID string, courseID int64, exportID string) (*dto.CourseExportResponse, error) {
+	const op _error.Op = "serv/GetExport"
+
+	if err := s.ValidateOwnershipCourse(c, userID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
+	}
+	notFound := _error.E(op, _error.NotExist, _error.Title("Export not found"), "The requested export could not be found or is not ready yet")
+	if _, err := uuid.Parse(exportID); err != nil {
+		return nil, notFound
+	}
+
+	key := courseExportKey(userID, courseID, exportID)
+	obj, err := s.blob.Open(c, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, notFound
+		}
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to get export"), err)
+	}
+	obj.Body.Close()
+
+	url, err := s.blob.SignedURL(c, key, exportURLTTL)
+	if err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to get export"), err)
+	}
+	return &dto.CourseExportResponse{ID: exportID, URL: url}, nil
+}
+
+// RunExport handles ExportCourseJob. A course deleted in the meantime is not
+// retried.
+func (s *courseService) RunExport(ctx context.Context, job dto.CourseExportJob) error {
+	course, err := s.repo.GetCourseByID(job.CourseID)
+	if err != nil {
+		if isKind(err, _error.NotExist) {
+			return jobs.Permanent(err)
+		}
+		return err
+	}
+	if course.UserID != job.UserID {
+		return jobs.Permanent(fmt.Errorf("course %d does not belong to user %s", job.CourseID, job.UserID))
+	}
+	tasks, err := s.repo.GetTasksByCourse(job.CourseID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(dto.CourseExport{
+		Course:     *dto.ToCourseResponse(course),
+		Tasks:      dto.ToTaskResponses(&tasks),
+		ExportedAt: time.Now(),
+	})
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+	key := courseExportKey(job.UserID, job.CourseID, job.ExportID)
+	if err := s.blob.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/json"); err != nil {
+		return err
+	}
+
+	return s.ns.Publish(ctx, dto.Notification{
+		UserID:   job.UserID,
+		Type:     dto.NotificationExportReady,
+		Title:    fmt.Sprintf("Your export of %s is ready", course.Name),
+		Body:     fmt.Sprintf("%d tasks exported", len(tasks)),
+		CourseID: job.CourseID,
+		Link:     fmt.Sprintf("/courses/%d/exports/%s", job.CourseID, job.ExportID),
+	})
+}
+
+func courseExportPrefix(userID string, courseID int64) string {
+	return "exports/" + userID + "/" + strconv.FormatInt(courseID, 10) + "/"
+}
+
+func courseExportKey(userID string, courseID int64, exportID string) string {
+	return courseExportPrefix(userID, courseID) + exportID + ".json"
+}
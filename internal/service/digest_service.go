@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
+	"courseworker/internal/repository"
+	_error "courseworker/pkg/error"
+	"courseworker/pkg/jobs"
+	"courseworker/pkg/mailer"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"log"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+type DigestService interface {
+	GetSettings(c *gin.Context, userID string) (*dto.DigestSettingsResponse, error)
+	UpdateSettings(c *gin.Context, userID string, req dto.DigestSettingsReq) (*dto.DigestSettingsResponse, error)
+	VerifyUnsubscribe(c *gin.Context, query dto.DigestUnsubscribeQuery) error
+	Unsubscribe(c *gin.Context, query dto.DigestUnsubscribeQuery) error
+}
+
+type digestService struct {
+	repo repository.DigestRepository
+}
+
+func NewDigestService(r repository.DigestRepository) DigestService {
+	return &digestService{r}
+}
+
+func (s *digestService) GetSettings(c *gin.Context, userID string) (*dto.DigestSettingsResponse, error) {
+	const op _error.Op = "serv/GetDigestSettings"
+
+	sub, err := s.repo.GetDigestSubscription(userID)
+	if err != nil {
+		if isKind(err, _error.NotExist) {
+			return dto.DefaultDigestSettings(), nil
+		}
+		return nil, _error.E(op, _error.Title("Failed to get digest settings"), err)
+	}
+	return dto.ToDigestSettingsResponse(sub), nil
+}
+
+func (s *digestService) UpdateSettings(c *gin.Context, userID string, req dto.DigestSettingsReq) (*dto.DigestSettingsResponse, error) {
+	const op _error.Op = "serv/UpdateDigestSettings"
+
+	param := sqlc.UpsertDigestSubscriptionParams{
+		UserID:    userID,
+		Frequency: req.Frequency,
+		SendHour:  int32(*req.SendHour),
+		Weekday:   int32(dto.DefaultDigestSettings().Weekday),
+	}
+	if req.Weekday != nil {
+		param.Weekday = int32(*req.Weekday)
+	}
+	if _, err := s.repo.UpsertDigestSubscription(param); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to update digest settings"), err)
+	}
+	return dto.ToDigestSettingsResponse(&sqlc.DigestSubscription{
+		UserID:    param.UserID,
+		Frequency: param.Frequency,
+		SendHour:  param.SendHour,
+		Weekday:   param.Weekday,
+	}), nil
+}
+
+// VerifyUnsubscribe checks that the unsubscribe link was signed for its user.
+func (s *digestService) VerifyUnsubscribe(c *gin.Context, query dto.DigestUnsubscribeQuery) error {
+	const op _error.Op = "serv/VerifyDigestUnsubscribe"
+
+	expected := digestUnsubscribeSignature(query.UserID)
+	given, err := hex.DecodeString(query.Signature)
+	if err != nil || !hmac.Equal(given, expected) {
+		return _error.E(op, _error.Forbidden, _error.Title("Failed to unsubscribe"), "unsubscribe link is invalid")
+	}
+	return nil
+}
+
+// Unsubscribe turns the digest off for the user the signed link was made
+// for. It needs no login so it works straight from the email.
+func (s *digestService) Unsubscribe(c *gin.Context, query dto.DigestUnsubscribeQuery) error {
+	const op _error.Op = "serv/UnsubscribeDigest"
+
+	if err := s.VerifyUnsubscribe(c, query); err != nil {
+		return _error.E(op, err)
+	}
+	if _, err := s.repo.UnsubscribeDigest(query.UserID); err != nil {
+		return _error.E(op, _error.Title("Failed to unsubscribe"), err)
+	}
+	return nil
+}
+
+// digestUnsubscribeSignature signs the user ID so unsubscribe links cannot be
+// forged for other users. The links do not expire.
+func digestUnsubscribeSignature(userID string) []byte {
+	mac := hmac.New(sha256.New, digestUnsubscribeKey())
+	mac.Write([]byte("digest-unsubscribe:" + userID))
+	return mac.Sum(nil)
+}
+
+// digestUnsubscribeKey is DIGEST_UNSUBSCRIBE_SECRET, or else a key derived
+// from JWT_SECRET_KEY, so the links are never signed with the key that signs
+// access tokens.
+func digestUnsubscribeKey() []byte {
+	if secret := os.Getenv("DIGEST_UNSUBSCRIBE_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+	mac := hmac.New(sha256.New, []byte(os.Getenv("JWT_SECRET_KEY")))
+	mac.Write([]byte("courseworker digest unsubscribe key"))
+	return mac.Sum(nil)
+}
+
+func digestUnsubscribeURL(userID string) string {
+	q := url.Values{}
+	q.Set("uid", userID)
+	q.Set("sig", hex.EncodeToString(digestUnsubscribeSignature(userID)))
+	return os.Getenv("BASE_URL") + "/digest/unsubscribe?" + q.Encode()
+}
+
+type digest struct {
+	UserName         string
+	Frequency        string
+	Courses          []digestCourse
+	OverdueCount     int
+	DueTodayCount    int
+	DueThisWeekCount int
+}
+
+type digestCourse struct {
+	Name        string
+	Overdue     []digestTask
+	DueToday    []digestTask
+	DueThisWeek []digestTask
+}
+
+type digestTask struct {
+	Title     string
+	Type      string
+	Deadline  time.Time
+	Highlight bool
+}
+
+// Each sent digest is claimed under "digest-sent:<user>:<local date>" so
+// replicas scanning at the same time send it once. last_sent_at keeps it from
+// going out twice on one day if Redis loses the claim.
+const (
+	digestSentKeyPrefix = "digest-sent:"
+	defaultDigestTick   = 5 * time.Minute
+)
+
+// DigestScheduler sends daily and weekly task digests at each subscriber's
+// chosen local hour.
+type DigestScheduler struct {
+	repo       repository.DigestRepository
+	taskRepo   repository.TaskRepository
+	courseRepo repository.CourseRepository
+	rd         *redis.Client
+	queue      *jobs.Queue
+	interval   time.Duration
+}
+
+func NewDigestScheduler(r repository.DigestRepository, tr repository.TaskRepository, cr repository.CourseRepository, rdc *redis.Client, queue *jobs.Queue, interval time.Duration) *DigestScheduler {
+	if interval <= 0 {
+		interval = defaultDigestTick
+	}
+	return &DigestScheduler{
+		repo:       r,
+		taskRepo:   tr,
+		courseRepo: cr,
+		rd:         rdc,
+		queue:      queue,
+		interval:   interval,
+	}
+}
+
+// Run scans once per interval until ctx is cancelled.
+func (s *DigestScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(ctx, time.Now().UTC()); err != nil {
+			log.Printf("Digest scan failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick sends the digests that are due at now: during the subscriber's local
+// send hour (for weekly digests, on their weekday) when nothing was sent yet
+// that local day. Outside that hour nothing is sent, so saving the settings
+// later in the day does not trigger a digest right away, and a day the
+// scheduler was down for the whole hour is skipped.
+func (s *DigestScheduler) Tick(ctx context.Context, now time.Time) error {
+	const op _error.Op = "serv/DigestTick"
+
+	subs, err := s.repo.GetActiveDigestSubscriptions()
+	if err != nil {
+		return _error.E(op, err)
+	}
+
+	for _, sub := range subs {
+		loc, err := time.LoadLocation(sub.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		local := now.In(loc)
+		if local.Hour() != int(sub.SendHour) {
+			continue
+		}
+		if sub.Frequency == dto.DigestWeekly && int(local.Weekday()) != int(sub.Weekday) {
+			continue
+		}
+		today := local.Format(time.DateOnly)
+		if sub.LastSentAt.Valid && sub.LastSentAt.Time.In(loc).Format(time.DateOnly) == today {
+			continue
+		}
+
+		if err := s.send(ctx, sub, local, today); err != nil {
+			log.Printf("Failed to send digest to user %s: %v", sub.UserID, err)
+		}
+	}
+	return nil
+}
+
+func (s *DigestScheduler) send(ctx context.Context, sub sqlc.GetActiveDigestSubscriptionsRow, local time.Time, today string) error {
+	key := digestSentKeyPrefix + sub.UserID + ":" + today
+	claimed, err := s.rd.SetNX(ctx, key, 1, 36*time.Hour).Result()
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	d, err := s.build(sub, local)
+	if err == nil && len(d.Courses) > 0 {
+		err = sendEmail(ctx, s.queue, mailer.Email{
+			To:             sub.Email,
+			Template:       mailer.Digest,
+			Locale:         sub.Locale,
+			Data:           d,
+			UnsubscribeURL: digestUnsubscribeURL(sub.UserID),
+		})
+	}
+	if err != nil {
+		if delErr := s.rd.Del(ctx, key).Err(); delErr != nil {
+			log.Printf("Redis Delete failed: %v", delErr)
+		}
+		return err
+	}
+
+	// Empty digests are skipped but still count as sent for the day.
+	_, err = s.repo.SetDigestSentAt(sqlc.SetDigestSentAtParams{
+		LastSentAt: sql.NullTime{Time: local.UTC(), Valid: true},
+		UserID:     sub.UserID,
+	})
+	return err
+}
+
+// build groups the user's open tasks by course into overdue, due today and
+// due within the next seven days, with highlighted tasks first.
+func (s *DigestScheduler) build(sub sqlc.GetActiveDigestSubscriptionsRow, local time.Time) (*digest, error) {
+	tasks, err := s.taskRepo.GetAllTasks(sub.UserID)
+	if err != nil {
+		return nil, err
+	}
+	courses, err := s.courseRepo.GetAllCourses(sub.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	startOfDay := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	endOfDay := startOfDay.AddDate(0, 0, 1)
+	endOfWeek := startOfDay.AddDate(0, 0, 7)
+
+	byCourse := map[int64]*digestCourse{}
+	for _, t := range tasks {
+		if t.IsDone || !t.Deadline.Valid {
+			continue
+		}
+		deadline := t.Deadline.Time.In(local.Location())
+		if !deadline.Before(endOfWeek) {
+			continue
+		}
+
+		dc, ok := byCourse[t.CourseID]
+		if !ok {
+			dc = &digestCourse{}
+			byCourse[t.CourseID] = dc
+		}
+		task := digestTask{Title: t.Title, Type: t.Type, Deadline: deadline, Highlight: t.Highlight}
+		switch {
+		case deadline.Before(local):
+			dc.Overdue = append(dc.Overdue, task)
+		case deadline.Before(endOfDay):
+			dc.DueToday = append(dc.DueToday, task)
+		default:
+			dc.DueThisWeek = append(dc.DueThisWeek, task)
+		}
+	}
+
+	d := &digest{
+		UserName:  sub.Name,
+		Frequency: sub.Frequency,
+	}
+	for _, c := range courses {
+		dc, ok := byCourse[c.ID]
+		if !ok {
+			continue
+		}
+		dc.Name = c.Name
+		for _, list := range [][]digestTask{dc.Overdue, dc.DueToday, dc.DueThisWeek} {
+			sortDigestTasks(list)
+		}
+		d.Courses = append(d.Courses, *dc)
+		d.OverdueCount += len(dc.Overdue)
+		d.DueTodayCount += len(dc.DueToday)
+		d.DueThisWeekCount += len(dc.DueThisWeek)
+	}
+	sort.SliceStable(d.Courses, func(i, j int) bool { return d.Courses[i].Name < d.Courses[j].Name })
+	return d, nil
+}
+
+func sortDigestTasks(tasks []digestTask) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Highlight != tasks[j].Highlight {
+			return tasks[i].Highlight
+		}
+		return tasks[i].Deadline.Before(tasks[j].Deadline)
+	})
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"courseworker/pkg/jobs"
+	"courseworker/pkg/mailer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SendEmailJob delivers one rendered email. Emails are sent by the job
+// workers so delivery failures are retried and nothing is lost on shutdown.
+var SendEmailJob = jobs.Type[mailer.Message]{Name: "email.send"}
+
+// sendEmail renders the email and queues it for delivery.
+func sendEmail(ctx context.Context, q *jobs.Queue, email mailer.Email) error {
+	msg, err := mailer.Render(email)
+	if err != nil {
+		return err
+	}
+	return SendEmailJob.Enqueue(ctx, q, msg)
+}
+
+// requestLocale is the email locale preferred by the client of the request.
+func requestLocale(c *gin.Context) string {
+	return mailer.MatchLocale(c.GetHeader("Accept-Language"))
+}
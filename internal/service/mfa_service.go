@@ -0,0 +1,418 @@
+package service
+
+import (
+	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
+	"courseworker/internal/repository"
+	"courseworker/pkg/bcrypt"
+	_error "courseworker/pkg/error"
+	"crypto/rand"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/redis/go-redis/v9"
+)
+
+// A password (or provider) login of an account with TOTP enabled stops at a
+// pending state kept under "mfa-pending:<token hash>". The token only buys a
+// few attempts at the second factor. Accepted TOTP codes are remembered under
+// "mfa-totp-used:<uid>:<code>" so a code cannot be replayed within its window.
+const (
+	mfaPendingKeyPrefix  = "mfa-pending:"
+	mfaTOTPUsedKeyPrefix = "mfa-totp-used:"
+	mfaPendingTTL        = 5 * time.Minute
+	maxMFAAttempts       = 5
+
+	recoveryCodeCount    = 10
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+var totpOpts = totp.ValidateOpts{
+	Period:    30,
+	Skew:      1,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+type MFAService interface {
+	GetStatus(c *gin.Context, userID string) (*dto.MFAStatusResponse, error)
+	EnrollTOTP(c *gin.Context, userID string) (*dto.TOTPEnrollResponse, error)
+	EnableTOTP(c *gin.Context, userID string, req dto.MFACodeReq) (*dto.RecoveryCodesResponse, error)
+	DisableTOTP(c *gin.Context, userID string, req dto.MFADisableReq) error
+	RegenerateRecoveryCodes(c *gin.Context, userID string, req dto.MFACodeReq) (*dto.RecoveryCodesResponse, error)
+	CompleteLogin(c *gin.Context, userID string) (*dto.TokenResp, error)
+	VerifyLogin(c *gin.Context, req dto.MFALoginReq) (*dto.TokenResp, error)
+}
+
+type mfaService struct {
+	repo     repository.UserMFARepository
+	userRepo repository.UserRepository
+	rd       *redis.Client
+	ss       SessionService
+}
+
+func NewMFAService(r repository.UserMFARepository, ur repository.UserRepository, rdc *redis.Client, sessionServ SessionService) MFAService {
+	return &mfaService{
+		repo:     r,
+		userRepo: ur,
+		rd:       rdc,
+		ss:       sessionServ,
+	}
+}
+
+func (s *mfaService) GetStatus(c *gin.Context, userID string) (*dto.MFAStatusResponse, error) {
+	const op _error.Op = "serv/GetMFAStatus"
+
+	mfa, err := s.repo.GetUserMFA(userID)
+	if err != nil {
+		if isKind(err, _error.NotExist) {
+			return &dto.MFAStatusResponse{}, nil
+		}
+		return nil, _error.E(op, _error.Title("Failed to get two-factor status"), err)
+	}
+	if !mfa.EnabledAt.Valid {
+		return &dto.MFAStatusResponse{}, nil
+	}
+
+	remaining, err := s.repo.CountUnusedRecoveryCodes(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get two-factor status"), err)
+	}
+	return &dto.MFAStatusResponse{
+		Enabled:                true,
+		EnabledAt:              &mfa.EnabledAt.Time,
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+// EnrollTOTP creates a new, not yet enabled secret. Calling it again before
+// verification replaces the pending secret.
+func (s *mfaService) EnrollTOTP(c *gin.Context, userID string) (*dto.TOTPEnrollResponse, error) {
+	const op _error.Op = "serv/EnrollTOTP"
+
+	if mfa, err := s.repo.GetUserMFA(userID); err == nil && mfa.EnabledAt.Valid {
+		return nil, _error.E(op, _error.Exist, _error.Title("Failed to enroll"), "two-factor authentication is already enabled")
+	} else if err != nil && !isKind(err, _error.NotExist) {
+		return nil, _error.E(op, _error.Title("Failed to enroll"), err)
+	}
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get user"), err)
+	}
+
+	issuer := os.Getenv("FROM_NAME")
+	if issuer == "" {
+		issuer = "CourseWorker"
+	}
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: user.Email,
+	})
+	if err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to enroll"), err)
+	}
+
+	if _, err := s.repo.UpsertUserMFA(sqlc.UpsertUserMFAParams{
+		UserID:     userID,
+		TotpSecret: key.Secret(),
+	}); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to enroll"), err)
+	}
+	return &dto.TOTPEnrollResponse{
+		Secret:     key.Secret(),
+		OTPAuthURI: key.URL(),
+	}, nil
+}
+
+// EnableTOTP turns two-factor authentication on once the user proves the
+// authenticator app produces valid codes, and hands out recovery codes.
+func (s *mfaService) EnableTOTP(c *gin.Context, userID string, req dto.MFACodeReq) (*dto.RecoveryCodesResponse, error) {
+	const op _error.Op = "serv/EnableTOTP"
+
+	mfa, err := s.repo.GetUserMFA(userID)
+	if err != nil {
+		if isKind(err, _error.NotExist) {
+			return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to enable two-factor authentication"), "start the enrollment first")
+		}
+		return nil, _error.E(op, _error.Title("Failed to enable two-factor authentication"), err)
+	}
+	if mfa.EnabledAt.Valid {
+		return nil, _error.E(op, _error.Exist, _error.Title("Failed to enable two-factor authentication"), "two-factor authentication is already enabled")
+	}
+
+	ok, err := s.verifyTOTP(c, mfa, req.Code)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to enable two-factor authentication"), err)
+	}
+	if !ok {
+		return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to enable two-factor authentication"), "invalid code")
+	}
+
+	if _, err := s.repo.EnableUserMFA(userID); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to enable two-factor authentication"), err)
+	}
+	codes, err := s.replaceRecoveryCodes(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to create recovery codes"), err)
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP requires the password (when the account has one) and a current
+// second factor, so a hijacked session alone cannot strip the protection.
+func (s *mfaService) DisableTOTP(c *gin.Context, userID string, req dto.MFADisableReq) error {
+	const op _error.Op = "serv/DisableTOTP"
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to get user"), err)
+	}
+	if user.Password != "" {
+		if err := bcrypt.ValidateHash(req.Password, user.Password); err != nil {
+			return _error.E(op, _error.Forbidden, _error.Title("Failed to disable two-factor authentication"), "password is incorrect")
+		}
+	}
+
+	mfa, err := s.enabledMFA(op, userID)
+	if err != nil {
+		return err
+	}
+	ok, err := s.verifyCode(c, mfa, req.Code)
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to disable two-factor authentication"), err)
+	}
+	if !ok {
+		return _error.E(op, _error.Forbidden, _error.Title("Failed to disable two-factor authentication"), "invalid code")
+	}
+
+	if _, err := s.repo.DeleteRecoveryCodes(userID); err != nil {
+		return _error.E(op, _error.Title("Failed to disable two-factor authentication"), err)
+	}
+	if _, err := s.repo.DeleteUserMFA(userID); err != nil {
+		return _error.E(op, _error.Title("Failed to disable two-factor authentication"), err)
+	}
+	return nil
+}
+
+func (s *mfaService) RegenerateRecoveryCodes(c *gin.Context, userID string, req dto.MFACodeReq) (*dto.RecoveryCodesResponse, error) {
+	const op _error.Op = "serv/RegenerateRecoveryCodes"
+
+	mfa, err := s.enabledMFA(op, userID)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.verifyTOTP(c, mfa, req.Code)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to create recovery codes"), err)
+	}
+	if !ok {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Failed to create recovery codes"), "invalid code")
+	}
+
+	codes, err := s.replaceRecoveryCodes(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to create recovery codes"), err)
+	}
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// CompleteLogin is called once the first factor has been checked. Accounts
+// without two-factor authentication get a session right away; the others get
+// a short-lived token for POST /login/mfa.
+func (s *mfaService) CompleteLogin(c *gin.Context, userID string) (*dto.TokenResp, error) {
+	const op _error.Op = "serv/CompleteLogin"
+
+	mfa, err := s.repo.GetUserMFA(userID)
+	if err != nil && !isKind(err, _error.NotExist) {
+		return nil, _error.E(op, _error.Title("Failed to log in"), err)
+	}
+	if err != nil || !mfa.EnabledAt.Valid {
+		token, err := s.ss.CreateSession(c, userID)
+		if err != nil {
+			return nil, _error.E(op, err)
+		}
+		return token, nil
+	}
+
+	token, err := generateSecretToken()
+	if err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to log in"), err)
+	}
+	key := mfaPendingKeyPrefix + hashSecretToken(token)
+	pipe := s.rd.TxPipeline()
+	pipe.HSet(c, key, "user_id", userID, "attempts", 0)
+	pipe.Expire(c, key, mfaPendingTTL)
+	if _, err := pipe.Exec(c); err != nil {
+		return nil, _error.E(op, _error.Cache, _error.Title("Failed to log in"), err)
+	}
+	return &dto.TokenResp{MFARequired: true, MFAToken: token}, nil
+}
+
+func (s *mfaService) VerifyLogin(c *gin.Context, req dto.MFALoginReq) (*dto.TokenResp, error) {
+	const op _error.Op = "serv/VerifyLogin"
+
+	key := mfaPendingKeyPrefix + hashSecretToken(req.MFAToken)
+	userID, err := s.rd.HGet(c, key, "user_id").Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, _error.E(op, _error.Unauthorized, _error.Title("Failed to log in"), "login has expired, please log in again")
+		}
+		return nil, _error.E(op, _error.Cache, _error.Title("Failed to log in"), err)
+	}
+
+	attempts, err := s.rd.HIncrBy(c, key, "attempts", 1).Result()
+	if err != nil {
+		return nil, _error.E(op, _error.Cache, _error.Title("Failed to log in"), err)
+	}
+	if attempts > maxMFAAttempts {
+		s.rd.Del(c, key)
+		return nil, _error.E(op, _error.Unauthorized, _error.Title("Failed to log in"), "too many attempts, please log in again")
+	}
+
+	mfa, err := s.enabledMFA(op, userID)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.verifyCode(c, mfa, req.Code)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to log in"), err)
+	}
+	if !ok {
+		return nil, _error.E(op, _error.Unauthorized, _error.Title("Failed to log in"), "invalid code")
+	}
+
+	if err := s.rd.Del(c, key).Err(); err != nil {
+		return nil, _error.E(op, _error.Cache, _error.Title("Failed to log in"), err)
+	}
+	token, err := s.ss.CreateSession(c, userID)
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+	return token, nil
+}
+
+func (s *mfaService) enabledMFA(op _error.Op, userID string) (*sqlc.UserMfa, error) {
+	mfa, err := s.repo.GetUserMFA(userID)
+	if err != nil && !isKind(err, _error.NotExist) {
+		return nil, _error.E(op, _error.Title("Failed to get two-factor status"), err)
+	}
+	if err != nil || !mfa.EnabledAt.Valid {
+		return nil, _error.E(op, _error.InvalidRequest, _error.Title("Two-factor authentication is not enabled"), "two-factor authentication is not enabled")
+	}
+	return mfa, nil
+}
+
+// verifyCode accepts either a TOTP code or an unused recovery code, which is
+// used up on success.
+func (s *mfaService) verifyCode(c *gin.Context, mfa *sqlc.UserMfa, code string) (bool, error) {
+	code = normalizeMFACode(code)
+	if isTOTPCode(code) {
+		return s.verifyTOTP(c, mfa, code)
+	}
+	return s.useRecoveryCode(mfa.UserID, code)
+}
+
+// useRecoveryCode compares the code with each of the user's unused recovery
+// codes, since salted hashes cannot be looked up, and uses up the match.
+func (s *mfaService) useRecoveryCode(userID, code string) (bool, error) {
+	codes, err := s.repo.GetUnusedRecoveryCodes(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, rc := range codes {
+		if bcrypt.ValidateHash(code, rc.CodeHash) != nil {
+			continue
+		}
+		return s.repo.UseRecoveryCode(sqlc.UseRecoveryCodeParams{
+			ID:     rc.ID,
+			UserID: userID,
+		})
+	}
+	return false, nil
+}
+
+func (s *mfaService) verifyTOTP(c *gin.Context, mfa *sqlc.UserMfa, code string) (bool, error) {
+	const op _error.Op = "serv/verifyTOTP"
+
+	code = normalizeMFACode(code)
+	valid, err := totp.ValidateCustom(code, mfa.TotpSecret, time.Now().UTC(), totpOpts)
+	if err != nil || !valid {
+		return false, nil
+	}
+
+	// A code stays valid for up to three periods because of the allowed
+	// skew; remember it that long so it cannot be used twice.
+	ttl := time.Duration(totpOpts.Period*(2*totpOpts.Skew+1)) * time.Second
+	fresh, err := s.rd.SetNX(c, mfaTOTPUsedKeyPrefix+mfa.UserID+":"+code, 1, ttl).Result()
+	if err != nil {
+		return false, _error.E(op, _error.Cache, err)
+	}
+	return fresh, nil
+}
+
+func (s *mfaService) replaceRecoveryCodes(userID string) ([]string, error) {
+	if _, err := s.repo.DeleteRecoveryCodes(userID); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		hash, err := bcrypt.HashValue(normalizeMFACode(code))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.repo.CreateRecoveryCode(sqlc.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: hash,
+		}); err != nil {
+			return nil, err
+		}
+		codes[i] = code
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode returns a code such as "k7m2p-x9qfr". The alphabet
+// leaves out characters that are easy to misread.
+func generateRecoveryCode() (string, error) {
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	var sb strings.Builder
+	for i := 0; i < 10; i++ {
+		if i == 5 {
+			sb.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(recoveryCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+func normalizeMFACode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
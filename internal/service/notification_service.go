@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
+	"courseworker/internal/repository"
+	_error "courseworker/pkg/error"
+	"database/sql"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationPublisher raises in-app notifications. Producers depend on it
+// rather than on NotificationService, so adding a notification type never
+// means touching the inbox endpoints.
+type NotificationPublisher interface {
+	Publish(ctx context.Context, n dto.Notification) error
+}
+
+type NotificationService interface {
+	NotificationPublisher
+	GetNotifications(c *gin.Context, userID string, query dto.NotificationListQuery, page, limit int) (*dto.NotificationPage, error)
+	GetUnreadCount(c *gin.Context, userID string) (*dto.NotificationCountResponse, error)
+	MarkRead(c *gin.Context, userID string, notificationID int64) error
+	MarkAllRead(c *gin.Context, userID string) error
+	DeleteNotification(c *gin.Context, userID string, notificationID int64) error
+}
+
+type notificationService struct {
+	repo repository.NotificationRepository
+}
+
+func NewNotificationService(r repository.NotificationRepository) NotificationService {
+	return &notificationService{r}
+}
+
+// Column limits of notifications.title and notifications.body.
+const (
+	maxNotificationTitle = 255
+	maxNotificationBody  = 1000
+)
+
+func (s *notificationService) Publish(ctx context.Context, n dto.Notification) error {
+	const op _error.Op = "serv/PublishNotification"
+
+	switch n.Type {
+	case dto.NotificationDeadlineApproaching, dto.NotificationTaskOverdue,
+		dto.NotificationCourseShared, dto.NotificationExportReady:
+	default:
+		return _error.E(op, _error.Internal, fmt.Sprintf("unknown notification type %q", n.Type))
+	}
+
+	param := sqlc.CreateNotificationParams{
+		UserID: n.UserID,
+		Type:   n.Type,
+		Title:  truncateRunes(n.Title, maxNotificationTitle),
+		Body:   truncateRunes(n.Body, maxNotificationBody),
+		Link:   n.Link,
+	}
+	if n.CourseID != 0 {
+		param.CourseID = sql.NullInt64{Int64: n.CourseID, Valid: true}
+	}
+	if n.TaskID != "" {
+		param.TaskID = sql.NullString{String: n.TaskID, Valid: true}
+	}
+	if _, err := s.repo.CreateNotification(param); err != nil {
+		return _error.E(op, _error.Title("Failed to create notification"), err)
+	}
+	return nil
+}
+
+func (s *notificationService) GetNotifications(c *gin.Context, userID string, query dto.NotificationListQuery, page, limit int) (*dto.NotificationPage, error) {
+	const op _error.Op = "serv/GetNotifications"
+
+	notifications, err := s.repo.GetNotificationsByUserID(sqlc.GetNotificationsByUserIDParams{
+		UserID:     userID,
+		UnreadOnly: query.Unread,
+		Limit:      int32(limit),
+		Offset:     int32((page - 1) * limit),
+	})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get notifications"), err)
+	}
+
+	total, err := s.repo.CountNotificationsByUserID(sqlc.CountNotificationsByUserIDParams{
+		UserID:     userID,
+		UnreadOnly: query.Unread,
+	})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get notifications"), err)
+	}
+	unread := total
+	if !query.Unread {
+		unread, err = s.repo.CountUnreadNotifications(userID)
+		if err != nil {
+			return nil, _error.E(op, _error.Title("Failed to get notifications"), err)
+		}
+	}
+
+	return &dto.NotificationPage{
+		Notifications: dto.ToNotificationResponses(&notifications),
+		Page:          dto.PageMeta{Page: page, Limit: limit, Total: total},
+		UnreadCount:   unread,
+	}, nil
+}
+
+func (s *notificationService) GetUnreadCount(c *gin.Context, userID string) (*dto.NotificationCountResponse, error) {
+	const op _error.Op = "serv/GetUnreadNotificationCount"
+
+	unread, err := s.repo.CountUnreadNotifications(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get unread count"), err)
+	}
+	return &dto.NotificationCountResponse{UnreadCount: unread}, nil
+}
+
+// MarkRead is idempotent; marking a notification that is already read
+// succeeds without changing when it was first read.
+func (s *notificationService) MarkRead(c *gin.Context, userID string, notificationID int64) error {
+	const op _error.Op = "serv/MarkNotificationRead"
+
+	result, err := s.repo.MarkNotificationRead(sqlc.MarkNotificationReadParams{
+		ID:     notificationID,
+		UserID: userID,
+	})
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to mark notification as read"), err)
+	}
+	if affected, _ := result.RowsAffected(); affected > 0 {
+		return nil
+	}
+	if _, err := s.repo.GetNotificationByID(sqlc.GetNotificationByIDParams{
+		ID:     notificationID,
+		UserID: userID,
+	}); err != nil {
+		return _error.E(op, _error.Title("Failed to mark notification as read"), err)
+	}
+	return nil
+}
+
+func (s *notificationService) MarkAllRead(c *gin.Context, userID string) error {
+	const op _error.Op = "serv/MarkAllNotificationsRead"
+
+	if _, err := s.repo.MarkAllNotificationsRead(userID); err != nil {
+		return _error.E(op, _error.Title("Failed to mark notifications as read"), err)
+	}
+	return nil
+}
+
+func (s *notificationService) DeleteNotification(c *gin.Context, userID string, notificationID int64) error {
+	const op _error.Op = "serv/DeleteNotification"
+
+	if _, err := s.repo.DeleteNotification(sqlc.DeleteNotificationParams{
+		ID:     notificationID,
+		UserID: userID,
+	}); err != nil {
+		return _error.E(op, _error.Title("Failed to delete notification"), err)
+	}
+	return nil
+}
+
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
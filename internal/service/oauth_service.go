@@ -0,0 +1,310 @@
+package service
+
+import (
+	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
+	"courseworker/internal/repository"
+	_error "courseworker/pkg/error"
+	"courseworker/pkg/oauth"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// A login attempt is remembered under "oauth-state:<state>" together with its
+// PKCE verifier and ID token nonce. The state is also set as a cookie on the browser starting the
+// flow; the callback must present both, which stops login CSRF.
+const (
+	oauthStateKeyPrefix = "oauth-state:"
+	oauthStateTTL       = 10 * time.Minute
+	oauthStateCookie    = "oauth_state"
+	oauthCookiePath     = "/auth"
+)
+
+type OAuthService interface {
+	GetProviders(c *gin.Context) []string
+	AuthURL(c *gin.Context, provider, linkUserID string) (string, error)
+	Callback(c *gin.Context, provider string, query dto.OAuthCallbackQuery) (*dto.TokenResp, error)
+	GetIdentities(c *gin.Context, userID string) ([]dto.IdentityResponse, error)
+	UnlinkIdentity(c *gin.Context, userID, provider string) error
+}
+
+type oauthService struct {
+	repo      repository.UserRepository
+	identRepo repository.UserIdentityRepository
+	rd        *redis.Client
+	providers *oauth.Registry
+	mfa       MFAService
+}
+
+func NewOAuthService(r repository.UserRepository, ir repository.UserIdentityRepository, rdc *redis.Client, providers *oauth.Registry, mfaServ MFAService) OAuthService {
+	return &oauthService{
+		repo:      r,
+		identRepo: ir,
+		rd:        rdc,
+		providers: providers,
+		mfa:       mfaServ,
+	}
+}
+
+func (s *oauthService) GetProviders(c *gin.Context) []string {
+	names := s.providers.Names()
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
+
+func (s *oauthService) provider(c *gin.Context, op _error.Op, name string) (*oauth.Provider, error) {
+	p, err := s.providers.Get(c, name)
+	if err != nil {
+		if errors.Is(err, oauth.ErrUnknownProvider) {
+			return nil, _error.E(op, _error.NotExist, _error.Title("Provider not found"), fmt.Sprintf("unknown login provider %s", name))
+		}
+		return nil, _error.E(op, _error.Internal, _error.Title("Login provider is unavailable"), err)
+	}
+	return p, nil
+}
+
+// AuthURL starts a login with the provider. When linkUserID is set the
+// callback links the provider account to that user instead of logging in.
+func (s *oauthService) AuthURL(c *gin.Context, provider, linkUserID string) (string, error) {
+	const op _error.Op = "serv/AuthURL"
+
+	p, err := s.provider(c, op, provider)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := generateSecretToken()
+	if err != nil {
+		return "", _error.E(op, _error.Internal, _error.Title("Failed to start login"), err)
+	}
+	nonce, err := generateSecretToken()
+	if err != nil {
+		return "", _error.E(op, _error.Internal, _error.Title("Failed to start login"), err)
+	}
+	verifier := oauth.NewVerifier()
+
+	key := oauthStateKeyPrefix + state
+	pipe := s.rd.TxPipeline()
+	pipe.HSet(c, key, map[string]interface{}{
+		"provider": provider,
+		"verifier": verifier,
+		"nonce":    nonce,
+		"user_id":  linkUserID,
+	})
+	pipe.Expire(c, key, oauthStateTTL)
+	if _, err := pipe.Exec(c); err != nil {
+		return "", _error.E(op, _error.Cache, _error.Title("Failed to start login"), err)
+	}
+
+	setOAuthStateCookie(c, state, int(oauthStateTTL.Seconds()))
+	return p.AuthCodeURL(state, nonce, verifier), nil
+}
+
+// Callback finishes the flow started by AuthURL. It returns a token pair for
+// logins and nil for link requests.
+func (s *oauthService) Callback(c *gin.Context, provider string, query dto.OAuthCallbackQuery) (*dto.TokenResp, error) {
+	const op _error.Op = "serv/OAuthCallback"
+
+	cookie, _ := c.Cookie(oauthStateCookie)
+	setOAuthStateCookie(c, "", -1)
+
+	if query.Error != "" {
+		return nil, _error.E(op, _error.Unauthorized, _error.Title("Failed to log in"), fmt.Sprintf("authorization failed: %s", query.Error))
+	}
+	if query.State == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(query.State)) != 1 {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Failed to log in"), "login state does not match")
+	}
+
+	// The state is consumed whatever happens next so it cannot be replayed.
+	key := oauthStateKeyPrefix + query.State
+	pipe := s.rd.TxPipeline()
+	get := pipe.HGetAll(c, key)
+	pipe.Del(c, key)
+	if _, err := pipe.Exec(c); err != nil {
+		return nil, _error.E(op, _error.Cache, _error.Title("Failed to log in"), err)
+	}
+	values := get.Val()
+	if len(values) == 0 || values["provider"] != provider {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Failed to log in"), "login request is invalid or has expired")
+	}
+
+	p, err := s.provider(c, op, provider)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := p.Exchange(c, query.Code, values["nonce"], values["verifier"])
+	if err != nil {
+		return nil, _error.E(op, _error.Unauthorized, _error.Title("Failed to log in"), err)
+	}
+
+	if linkUserID := values["user_id"]; linkUserID != "" {
+		if err := s.link(op, linkUserID, provider, profile); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	userID, err := s.resolveUser(op, provider, profile, requestLocale(c))
+	if err != nil {
+		return nil, err
+	}
+	token, err := s.mfa.CompleteLogin(c, userID)
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+	return token, nil
+}
+
+// resolveUser finds the account behind a provider profile. Known identities
+// log in directly. Otherwise an account with the same, provider-verified email
+// gets the identity linked when the provider is trusted for email linking, and
+// failing that a new account is created with locale for its emails. Untrusted
+// providers never take over an existing account; its owner has to link them
+// while logged in.
+func (s *oauthService) resolveUser(op _error.Op, provider string, profile *oauth.Profile, locale string) (string, error) {
+	identity, err := s.identRepo.GetIdentity(sqlc.GetIdentityParams{
+		Provider: provider,
+		Subject:  profile.Subject,
+	})
+	if err == nil {
+		return identity.UserID, nil
+	}
+	if !isKind(err, _error.NotExist) {
+		return "", _error.E(op, _error.Title("Failed to log in"), err)
+	}
+
+	if !bool(profile.EmailVerified) {
+		return "", _error.E(op, _error.Forbidden, _error.Title("Failed to log in"), "the provider has not verified this email address")
+	}
+
+	user, err := s.repo.GetUserByEmail(profile.Email)
+	switch {
+	case err == nil:
+		if !s.providers.TrustsEmail(provider) {
+			return "", _error.E(
+				op, _error.Exist, _error.Title("Failed to log in"),
+				fmt.Sprintf("an account with this email already exists; log in and link your %s account from your profile", provider),
+			)
+		}
+		if err := s.link(op, user.ID, provider, profile); err != nil {
+			return "", err
+		}
+		return user.ID, nil
+	case !isKind(err, _error.NotExist):
+		return "", _error.E(op, _error.Title("Failed to log in"), err)
+	}
+
+	userID := uuid.New().String()
+	name := strings.TrimSpace(profile.Name)
+	if name == "" {
+		name = strings.Split(profile.Email, "@")[0]
+	}
+	// Accounts created through a provider have no password; one can be set
+	// through the password reset flow.
+	if err := s.repo.CreateAccount(repository.CreateAccountParams{
+		User: sqlc.CreateUserParams{
+			ID:       userID,
+			Name:     name,
+			Email:    profile.Email,
+			Password: "",
+			Locale:   locale,
+		},
+		ProfileImg: sql.NullString{String: profile.Picture, Valid: profile.Picture != ""},
+		TaskTypes:  defaultTaskTypeParams(userID),
+		Identity: &sqlc.CreateIdentityParams{
+			UserID:   userID,
+			Provider: provider,
+			Subject:  profile.Subject,
+			Email:    profile.Email,
+		},
+	}); err != nil {
+		return "", _error.E(op, _error.Title("Failed to create user"), err)
+	}
+	return userID, nil
+}
+
+func (s *oauthService) link(op _error.Op, userID, provider string, profile *oauth.Profile) error {
+	identity, err := s.identRepo.GetIdentity(sqlc.GetIdentityParams{
+		Provider: provider,
+		Subject:  profile.Subject,
+	})
+	if err == nil {
+		if identity.UserID != userID {
+			return _error.E(op, _error.Exist, _error.Title("Failed to link account"), fmt.Sprintf("this %s account is linked to another user", provider))
+		}
+		return nil
+	}
+	if !isKind(err, _error.NotExist) {
+		return _error.E(op, _error.Title("Failed to link account"), err)
+	}
+
+	if _, err := s.identRepo.CreateIdentity(sqlc.CreateIdentityParams{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  profile.Subject,
+		Email:    profile.Email,
+	}); err != nil {
+		return _error.E(op, _error.Title("Failed to link account"), err)
+	}
+	return nil
+}
+
+func (s *oauthService) GetIdentities(c *gin.Context, userID string) ([]dto.IdentityResponse, error) {
+	const op _error.Op = "serv/GetIdentities"
+
+	identities, err := s.identRepo.GetIdentitiesByUserID(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get linked accounts"), err)
+	}
+	return dto.ToIdentityResponses(&identities), nil
+}
+
+// UnlinkIdentity removes a provider from the account unless it is the only
+// way left to log in.
+func (s *oauthService) UnlinkIdentity(c *gin.Context, userID, provider string) error {
+	const op _error.Op = "serv/UnlinkIdentity"
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to get user"), err)
+	}
+	identities, err := s.identRepo.GetIdentitiesByUserID(userID)
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to get linked accounts"), err)
+	}
+	if user.Password == "" && len(identities) <= 1 {
+		return _error.E(op, _error.InvalidRequest, _error.Title("Failed to unlink account"), "set a password before unlinking your only login method")
+	}
+
+	if _, err := s.identRepo.DeleteIdentity(sqlc.DeleteIdentityParams{
+		UserID:   userID,
+		Provider: provider,
+	}); err != nil {
+		return _error.E(op, _error.Title("Failed to unlink account"), err)
+	}
+	return nil
+}
+
+func setOAuthStateCookie(c *gin.Context, state string, maxAge int) {
+	secure := strings.HasPrefix(os.Getenv("BASE_URL"), "https://")
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, state, maxAge, oauthCookiePath, "", secure, true)
+}
+
+// isKind reports whether err carries a problem of the given kind.
+func isKind(err error, kind _error.Kind) bool {
+	var problem *_error.Problem
+	return errors.As(err, &problem) && problem.Kind == kind
+}
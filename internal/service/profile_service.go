@@ -0,0 +1,412 @@
+package service
+
+import (
+	"bytes"
+	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
+	"courseworker/internal/repository"
+	"courseworker/pkg/bcrypt"
+	_error "courseworker/pkg/error"
+	"courseworker/pkg/imaging"
+	"courseworker/pkg/jobs"
+	_jwt "courseworker/pkg/jwt"
+	"courseworker/pkg/mailer"
+	"courseworker/pkg/storage"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	maxAvatarSize = 5 << 20
+	// defaultAvatarSize is the variant returned as profile_img.
+	defaultAvatarSize = 256
+
+	emailChangeKeyPrefix = "email-change:"
+	emailChangeTTL       = 15 * time.Minute
+)
+
+// avatarSizes are the square variants rendered for every uploaded avatar.
+var avatarSizes = []int{64, 128, defaultAvatarSize}
+
+type ProfileService interface {
+	GetProfile(c *gin.Context, userID string) (*dto.UserResponse, error)
+	UpdateProfile(c *gin.Context, userID string, req dto.ProfileUpdateReq) (*dto.UserResponse, error)
+	UploadAvatar(c *gin.Context, userID string, file *multipart.FileHeader) (*dto.UserResponse, error)
+	RemoveAvatar(c *gin.Context, userID string) (*dto.UserResponse, error)
+	RequestEmailChange(c *gin.Context, userID string, req dto.EmailChangeReq) (*dto.RegisterUserResp, error)
+	ConfirmEmailChange(c *gin.Context, token string) (*dto.UserResponse, error)
+	ChangePassword(c *gin.Context, userID string, req dto.PasswordChangeReq) (*dto.TokenResp, error)
+	DeleteAccount(c *gin.Context, userID string, req dto.AccountDeleteReq) error
+}
+
+type profileService struct {
+	repo  repository.UserRepository
+	rd    *redis.Client
+	blob  storage.Blob
+	us    UserService
+	ss    SessionService
+	queue *jobs.Queue
+}
+
+func NewProfileService(r repository.UserRepository, rdc *redis.Client, blob storage.Blob, userServ UserService, sessionServ SessionService, queue *jobs.Queue) ProfileService {
+	return &profileService{
+		repo:  r,
+		rd:    rdc,
+		blob:  blob,
+		us:    userServ,
+		ss:    sessionServ,
+		queue: queue,
+	}
+}
+
+func (s *profileService) GetProfile(c *gin.Context, userID string) (*dto.UserResponse, error) {
+	const op _error.Op = "serv/GetProfile"
+	resp, err := s.us.GetUserByID(c, userID)
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+	return resp, nil
+}
+
+func (s *profileService) UpdateProfile(c *gin.Context, userID string, req dto.ProfileUpdateReq) (*dto.UserResponse, error) {
+	const op _error.Op = "serv/UpdateProfile"
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get user"), err)
+	}
+
+	param := sqlc.UpdateUserProfileParams{
+		Name:     user.Name,
+		Timezone: user.Timezone,
+		Locale:   user.Locale,
+		ID:       userID,
+	}
+	if req.Name != nil {
+		param.Name = strings.TrimSpace(*req.Name)
+		if param.Name == "" {
+			return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to update profile"), "name must not be empty")
+		}
+	}
+	if req.Timezone != nil {
+		if !validTimezone(*req.Timezone) {
+			return nil, _error.E(
+				op, _error.InvalidRequest, _error.Title("Failed to update profile"),
+				fmt.Sprintf("%s is not a valid IANA timezone", *req.Timezone),
+			)
+		}
+		param.Timezone = *req.Timezone
+	}
+	if req.Locale != nil {
+		if mailer.MatchLocale(*req.Locale) != *req.Locale {
+			return nil, _error.E(
+				op, _error.InvalidRequest, _error.Title("Failed to update profile"),
+				fmt.Sprintf("%s is not a supported locale", *req.Locale),
+			)
+		}
+		param.Locale = *req.Locale
+	}
+
+	if _, err := s.repo.UpdateUserProfile(param); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to update profile"), err)
+	}
+	if err := s.rd.Set(c, userTimezoneKeyPrefix+userID, param.Timezone, 0).Err(); err != nil {
+		log.Printf("Redis Set failed: %v", err)
+	}
+
+	return s.GetProfile(c, userID)
+}
+
+// UploadAvatar renders the picture into every avatar size and replaces the
+// previous avatar. profile_img stores the key prefix shared by the variants.
+func (s *profileService) UploadAvatar(c *gin.Context, userID string, file *multipart.FileHeader) (*dto.UserResponse, error) {
+	const op _error.Op = "serv/UploadAvatar"
+
+	if file.Size > maxAvatarSize {
+		return nil, _error.E(
+			op, _error.InvalidRequest, _error.Title("Failed to upload avatar"),
+			fmt.Sprintf("avatar must not be larger than %d MB", maxAvatarSize>>20),
+		)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to upload avatar"), err)
+	}
+	defer f.Close()
+
+	img, _, err := imaging.Decode(f)
+	if err != nil {
+		return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to upload avatar"), err)
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get user"), err)
+	}
+
+	base := "users/" + userID + "/avatar-" + uuid.New().String()
+	for _, size := range avatarSizes {
+		data, err := imaging.SquareJPEG(img, size)
+		if err == nil {
+			err = s.blob.Put(c, avatarKey(base, size), bytes.NewReader(data), int64(len(data)), "image/jpeg")
+		}
+		if err != nil {
+			s.deleteAvatar(c, base)
+			return nil, _error.E(op, _error.Internal, _error.Title("Failed to upload avatar"), err)
+		}
+	}
+
+	if _, err := s.repo.UpdateUserProfileImg(sqlc.UpdateUserProfileImgParams{
+		ProfileImg: sql.NullString{String: base, Valid: true},
+		ID:         userID,
+	}); err != nil {
+		s.deleteAvatar(c, base)
+		return nil, _error.E(op, _error.Title("Failed to upload avatar"), err)
+	}
+	s.deleteAvatar(c, user.ProfileImg.String)
+
+	return s.GetProfile(c, userID)
+}
+
+func (s *profileService) RemoveAvatar(c *gin.Context, userID string) (*dto.UserResponse, error) {
+	const op _error.Op = "serv/RemoveAvatar"
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get user"), err)
+	}
+
+	if user.ProfileImg.Valid {
+		if _, err := s.repo.UpdateUserProfileImg(sqlc.UpdateUserProfileImgParams{ID: userID}); err != nil {
+			return nil, _error.E(op, _error.Title("Failed to remove avatar"), err)
+		}
+		s.deleteAvatar(c, user.ProfileImg.String)
+	}
+
+	return s.GetProfile(c, userID)
+}
+
+// RequestEmailChange mails a confirmation link to the new address. The
+// address only replaces the current one once the link is opened.
+func (s *profileService) RequestEmailChange(c *gin.Context, userID string, req dto.EmailChangeReq) (*dto.RegisterUserResp, error) {
+	const op _error.Op = "serv/RequestEmailChange"
+
+	isExist, err := s.us.EmailExists(req.Email)
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+	if isExist {
+		return nil, _error.E(op, _error.Exist, _error.Title("Failed to change email"), "email has been used")
+	}
+
+	changeID := uuid.New().String()
+	key := emailChangeKeyPrefix + changeID
+	pipe := s.rd.TxPipeline()
+	pipe.HSet(c, key, map[string]interface{}{
+		"user_id": userID,
+		"email":   req.Email,
+	})
+	pipe.Expire(c, key, emailChangeTTL)
+	if _, err := pipe.Exec(c); err != nil {
+		return nil, _error.E(op, _error.Cache, _error.Title("Failed to store data"), err)
+	}
+
+	token, err := _jwt.GenerateConfirmationToken(changeID)
+	if err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to send email"), err)
+	}
+
+	link := fmt.Sprintf("%s/me/email/confirm?token=%s", os.Getenv("BASE_URL"), token)
+	if err := sendEmail(c, s.queue, mailer.Email{
+		To:       req.Email,
+		Template: mailer.EmailChange,
+		Locale:   requestLocale(c),
+		Data:     mailer.LinkData{Link: link, ExpiryMinutes: int(emailChangeTTL.Minutes())},
+	}); err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to send email"), err)
+	}
+
+	return &dto.RegisterUserResp{
+		Email: req.Email,
+	}, nil
+}
+
+func (s *profileService) ConfirmEmailChange(c *gin.Context, token string) (*dto.UserResponse, error) {
+	const op _error.Op = "serv/ConfirmEmailChange"
+
+	claims, err := s.us.ValidateTokenAndClaims(c, token)
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+
+	key := emailChangeKeyPrefix + claims.TempUserID
+	values, err := s.rd.HGetAll(c, key).Result()
+	if err != nil {
+		return nil, _error.E(op, _error.Cache, _error.Title("Failed to change email"), err)
+	}
+	if len(values) == 0 {
+		return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to change email"), "confirmation link is invalid or has expired")
+	}
+	if err := s.rd.Del(c, key).Err(); err != nil {
+		log.Printf("Redis Delete failed: %v", err)
+	}
+
+	// The address may have been registered while the link was pending.
+	isExist, err := s.us.EmailExists(values["email"])
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+	if isExist {
+		return nil, _error.E(op, _error.Exist, _error.Title("Failed to change email"), "email has been used")
+	}
+
+	if _, err := s.repo.UpdateUserEmail(sqlc.UpdateUserEmailParams{
+		Email: values["email"],
+		ID:    values["user_id"],
+	}); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to change email"), err)
+	}
+
+	return s.GetProfile(c, values["user_id"])
+}
+
+// ChangePassword replaces the password after checking the current one. Every
+// other session is logged out and a fresh token pair is returned for the
+// caller.
+func (s *profileService) ChangePassword(c *gin.Context, userID string, req dto.PasswordChangeReq) (*dto.TokenResp, error) {
+	const op _error.Op = "serv/ChangePassword"
+
+	if req.NewPassword != req.ConfirmPassword {
+		return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to change password"), "password confirmation does not match")
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get user"), err)
+	}
+	if err := bcrypt.ValidateHash(req.CurrentPassword, user.Password); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Failed to change password"), "current password is incorrect")
+	}
+
+	hashed, err := bcrypt.HashValue(req.NewPassword)
+	if err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to change password"), err)
+	}
+	if _, err := s.repo.UpdateUserPassword(sqlc.UpdateUserPasswordParams{
+		Password: hashed,
+		ID:       userID,
+	}); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to change password"), err)
+	}
+
+	if err := s.ss.RevokeAll(c, userID); err != nil {
+		return nil, _error.E(op, err)
+	}
+	token, err := s.ss.CreateSession(c, userID)
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+	return token, nil
+}
+
+// DeleteAccount deletes the user with everything they own. The user is
+// logged out everywhere first, so no token outlives the account.
+func (s *profileService) DeleteAccount(c *gin.Context, userID string, req dto.AccountDeleteReq) error {
+	const op _error.Op = "serv/DeleteAccount"
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to get user"), err)
+	}
+	if user.Password != "" {
+		if err := bcrypt.ValidateHash(req.Password, user.Password); err != nil {
+			return _error.E(op, _error.Forbidden, _error.Title("Failed to delete account"), "password is incorrect")
+		}
+	}
+
+	if err := s.ss.RevokeAll(c, userID); err != nil {
+		return _error.E(op, err)
+	}
+	deleted, err := s.repo.DeleteAccount(userID)
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to delete account"), err)
+	}
+
+	for _, courseID := range deleted.CourseIDs {
+		if err := s.rd.Del(c, "course:"+strconv.Itoa(int(courseID))).Err(); err != nil {
+			log.Printf("Redis Delete failed: %v", err)
+		}
+	}
+	for _, taskID := range deleted.TaskIDs {
+		if err := s.rd.Del(c, "task:"+taskID).Err(); err != nil {
+			log.Printf("Redis Delete failed: %v", err)
+		}
+		if err := s.blob.DeletePrefix(c, taskBlobPrefix(taskID)); err != nil {
+			log.Printf("Blob cleanup failed for task %s: %v", taskID, err)
+		}
+	}
+	if err := s.blob.DeletePrefix(c, attachmentBlobPrefix(userID)); err != nil {
+		log.Printf("Blob cleanup failed for attachments of user %s: %v", userID, err)
+	}
+	if err := s.blob.DeletePrefix(c, "exports/"+userID+"/"); err != nil {
+		log.Printf("Blob cleanup failed for exports of user %s: %v", userID, err)
+	}
+	if user.ProfileImg.Valid {
+		s.deleteAvatar(c, user.ProfileImg.String)
+	}
+	return nil
+}
+
+func (s *profileService) deleteAvatar(c *gin.Context, base string) {
+	if base == "" || isExternalURL(base) {
+		return
+	}
+	for _, size := range avatarSizes {
+		key := avatarKey(base, size)
+		if err := s.blob.Delete(c, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("Blob cleanup failed for %s: %v", key, err)
+		}
+	}
+}
+
+func avatarKey(base string, size int) string {
+	return base + "-" + strconv.Itoa(size) + ".jpg"
+}
+
+// signAvatar turns the stored profile_img into the URL of the default size
+// and a map of URLs keyed by size. Pictures hosted elsewhere, e.g. taken
+// from an OAuth provider, are passed through unchanged.
+func signAvatar(c *gin.Context, blob storage.Blob, stored string) (string, map[string]string) {
+	if stored == "" || isExternalURL(stored) {
+		return stored, nil
+	}
+	avatars := map[string]string{}
+	for _, size := range avatarSizes {
+		key := avatarKey(stored, size)
+		signed, err := blob.SignedURL(c, key, imageURLTTL)
+		if err != nil {
+			log.Printf("Failed to sign url for %s: %v", key, err)
+			continue
+		}
+		avatars[strconv.Itoa(size)] = signed
+	}
+	return avatars[strconv.Itoa(defaultAvatarSize)], avatars
+}
+
+func isExternalURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
+	"courseworker/internal/repository"
+	_error "courseworker/pkg/error"
+	"courseworker/pkg/jobs"
+	"courseworker/pkg/mailer"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+type ReminderService interface {
+	GetPreferences(c *gin.Context, userID string) (*dto.ReminderPreferencesResponse, error)
+	UpdatePreferences(c *gin.Context, userID string, req dto.ReminderPreferencesReq) (*dto.ReminderPreferencesResponse, error)
+}
+
+type reminderService struct {
+	repo repository.ReminderRepository
+}
+
+func NewReminderService(r repository.ReminderRepository) ReminderService {
+	return &reminderService{r}
+}
+
+func (s *reminderService) GetPreferences(c *gin.Context, userID string) (*dto.ReminderPreferencesResponse, error) {
+	const op _error.Op = "serv/GetReminderPreferences"
+
+	prefs, err := s.repo.GetReminderPreferences(userID)
+	if err != nil {
+		if isKind(err, _error.NotExist) {
+			return dto.DefaultReminderPreferences(), nil
+		}
+		return nil, _error.E(op, _error.Title("Failed to get reminder preferences"), err)
+	}
+	return dto.ToReminderPreferencesResponse(prefs), nil
+}
+
+func (s *reminderService) UpdatePreferences(c *gin.Context, userID string, req dto.ReminderPreferencesReq) (*dto.ReminderPreferencesResponse, error) {
+	const op _error.Op = "serv/UpdateReminderPreferences"
+
+	offsets := req.DefaultOffsets
+	if offsets == nil {
+		offsets = dto.DefaultReminderOffsets
+	}
+	channels := req.Channels
+	if channels == nil {
+		channels = dto.DefaultReminderChannels
+	}
+
+	param := sqlc.UpsertReminderPreferencesParams{
+		UserID:         userID,
+		Enabled:        *req.Enabled,
+		DefaultOffsets: dto.FormatReminderOffsets(offsets),
+		Channels:       strings.Join(channels, ","),
+	}
+	if _, err := s.repo.UpsertReminderPreferences(param); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to update reminder preferences"), err)
+	}
+	return dto.ToReminderPreferencesResponse(&sqlc.ReminderPreference{
+		UserID:         param.UserID,
+		Enabled:        param.Enabled,
+		DefaultOffsets: param.DefaultOffsets,
+		Channels:       param.Channels,
+	}), nil
+}
+
+// ReminderChannel delivers reminders to users, e.g. by email.
+type ReminderChannel interface {
+	Name() string
+	Send(ctx context.Context, r dto.Reminder) error
+}
+
+// The scheduler looks this far ahead for deadlines; longer offsets never fire.
+// Sent reminders are remembered under
+// "reminder-sent:<channel>:<task>:<deadline>:<offset>" until shortly after the
+// deadline, so restarts and concurrent replicas never send one twice and a
+// moved deadline gets fresh reminders. Overdue notices are remembered the
+// same way under "task-overdue:<task>:<deadline>".
+const (
+	reminderSentKeyPrefix = "reminder-sent:"
+	overdueSentKeyPrefix  = "task-overdue:"
+	maxReminderLead       = 30 * 24 * time.Hour
+	overdueLookback       = 24 * time.Hour
+	defaultReminderTick   = time.Minute
+)
+
+// ReminderScheduler periodically scans upcoming deadlines and sends the
+// reminders that are due.
+type ReminderScheduler struct {
+	repo          repository.ReminderRepository
+	rd            *redis.Client
+	notifications NotificationPublisher
+	channels      map[string]ReminderChannel
+	interval      time.Duration
+}
+
+// NewReminderScheduler delivers reminders over the given channels. With
+// notifications set it also tells users in the inbox about tasks whose
+// deadline has passed.
+func NewReminderScheduler(r repository.ReminderRepository, rdc *redis.Client, interval time.Duration, notifications NotificationPublisher, channels ...ReminderChannel) *ReminderScheduler {
+	if interval <= 0 {
+		interval = defaultReminderTick
+	}
+	byName := make(map[string]ReminderChannel, len(channels))
+	for _, ch := range channels {
+		byName[ch.Name()] = ch
+	}
+	return &ReminderScheduler{
+		repo:          r,
+		rd:            rdc,
+		notifications: notifications,
+		channels:      byName,
+		interval:      interval,
+	}
+}
+
+// Run scans once per interval until ctx is cancelled.
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(ctx, time.Now().UTC()); err != nil {
+			log.Printf("Reminder scan failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick sends every reminder due at now. For each task only the shortest
+// offset that has already passed is due, so a task created close to its
+// deadline gets one reminder rather than all the earlier ones at once.
+// Overdue notices go out after the reminders.
+func (s *ReminderScheduler) Tick(ctx context.Context, now time.Time) error {
+	const op _error.Op = "serv/ReminderTick"
+
+	tasks, err := s.repo.GetUpcomingDeadlines(sqlc.GetUpcomingDeadlinesParams{
+		FromTime: now,
+		ToTime:   now.Add(maxReminderLead),
+	})
+	if err != nil {
+		return _error.E(op, err)
+	}
+
+	for _, task := range tasks {
+		if !task.Deadline.Valid {
+			continue
+		}
+		offset, ok := dueOffset(task, now)
+		if !ok {
+			continue
+		}
+		for _, name := range strings.Split(task.Channels, ",") {
+			ch, ok := s.channels[strings.TrimSpace(name)]
+			if !ok {
+				continue
+			}
+			if err := s.deliver(ctx, ch, task, offset); err != nil {
+				log.Printf("Failed to send %s reminder for task %s: %v", ch.Name(), task.ID, err)
+			}
+		}
+	}
+
+	if s.notifications != nil {
+		if err := s.notifyOverdue(ctx, now); err != nil {
+			return _error.E(op, err)
+		}
+	}
+	return nil
+}
+
+// notifyOverdue publishes a task_overdue notification for every open task
+// whose deadline passed within overdueLookback, to users with in-app
+// reminders on. The lookback lets a scheduler that was down catch up.
+func (s *ReminderScheduler) notifyOverdue(ctx context.Context, now time.Time) error {
+	tasks, err := s.repo.GetUpcomingDeadlines(sqlc.GetUpcomingDeadlinesParams{
+		FromTime: now.Add(-overdueLookback),
+		ToTime:   now,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if !task.Deadline.Valid || !hasReminderChannel(task.Channels, dto.ReminderChannelInApp) {
+			continue
+		}
+		if err := publishOverdue(ctx, s.rd, s.notifications, dto.Notification{
+			UserID:   task.UserID,
+			Type:     dto.NotificationTaskOverdue,
+			Title:    fmt.Sprintf("%s is overdue", task.Title),
+			Body:     fmt.Sprintf("%s · was due %s", task.CourseName, task.Deadline.Time.In(userLocation(task.Timezone)).Format("Mon, 02 Jan 2006 15:04 MST")),
+			CourseID: task.CourseID,
+			TaskID:   task.ID,
+		}, task.Deadline.Time); err != nil {
+			log.Printf("Failed to publish overdue notification for task %s: %v", task.ID, err)
+		}
+	}
+	return nil
+}
+
+// publishOverdue publishes the task_overdue notification n once per task
+// deadline. The scheduler and task edits claim the same key, so neither
+// repeats the other's notice.
+func publishOverdue(ctx context.Context, rd *redis.Client, notifications NotificationPublisher, n dto.Notification, deadline time.Time) error {
+	key := fmt.Sprintf("%s%s:%d", overdueSentKeyPrefix, n.TaskID, deadline.Unix())
+	claimed, err := rd.SetNX(ctx, key, 1, overdueLookback+time.Hour).Result()
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	if err := notifications.Publish(ctx, n); err != nil {
+		if delErr := rd.Del(ctx, key).Err(); delErr != nil {
+			log.Printf("Redis Delete failed: %v", delErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func hasReminderChannel(channels, name string) bool {
+	for _, ch := range strings.Split(channels, ",") {
+		if strings.TrimSpace(ch) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func userLocation(timezone string) *time.Location {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// dueOffset returns the shortest reminder offset whose time has come. A task
+// is reminded at the user's default offsets plus those of its type, so the
+// user's choice holds for every task and types only add reminders.
+func dueOffset(task sqlc.GetUpcomingDeadlinesRow, now time.Time) (time.Duration, bool) {
+	minutes := append(dto.ParseReminderOffsets(task.DefaultOffsets), dto.ParseReminderOffsets(task.TypeOffsets)...)
+	sort.Ints(minutes)
+
+	for _, m := range minutes {
+		offset := time.Duration(m) * time.Minute
+		if !task.Deadline.Time.Add(-offset).After(now) {
+			return offset, true
+		}
+	}
+	return 0, false
+}
+
+// deliver claims the reminder in Redis before sending it and releases the
+// claim again if sending fails, so the next tick retries.
+func (s *ReminderScheduler) deliver(ctx context.Context, ch ReminderChannel, task sqlc.GetUpcomingDeadlinesRow, offset time.Duration) error {
+	key := fmt.Sprintf("%s%s:%s:%d:%d", reminderSentKeyPrefix, ch.Name(), task.ID, task.Deadline.Time.Unix(), int(offset.Minutes()))
+	ttl := time.Until(task.Deadline.Time) + time.Hour
+	claimed, err := s.rd.SetNX(ctx, key, 1, ttl).Result()
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	if err := ch.Send(ctx, dto.Reminder{
+		TaskID:     task.ID,
+		TaskTitle:  task.Title,
+		CourseID:   task.CourseID,
+		CourseName: task.CourseName,
+		UserID:     task.UserID,
+		UserName:   task.UserName,
+		Email:      task.Email,
+		Locale:     task.Locale,
+		Deadline:   task.Deadline.Time.In(userLocation(task.Timezone)),
+		Offset:     offset,
+	}); err != nil {
+		if delErr := s.rd.Del(ctx, key).Err(); delErr != nil {
+			log.Printf("Redis Delete failed: %v", delErr)
+		}
+		return err
+	}
+	return nil
+}
+
+type emailReminderChannel struct {
+	queue *jobs.Queue
+}
+
+// NewEmailReminderChannel queues reminder emails on the job queue like the
+// other account emails.
+func NewEmailReminderChannel(queue *jobs.Queue) ReminderChannel {
+	return &emailReminderChannel{queue}
+}
+
+func (ch *emailReminderChannel) Name() string {
+	return dto.ReminderChannelEmail
+}
+
+func (ch *emailReminderChannel) Send(ctx context.Context, r dto.Reminder) error {
+	// Reminders for tasks created close to their deadline go out late, so
+	// the subject uses the time actually left rather than the offset.
+	left := min(r.Offset, time.Until(r.Deadline))
+	return sendEmail(ctx, ch.queue, mailer.Email{
+		To:       r.Email,
+		Template: mailer.Reminder,
+		Locale:   r.Locale,
+		Data: mailer.ReminderData{
+			UserName:   r.UserName,
+			TaskTitle:  r.TaskTitle,
+			CourseName: r.CourseName,
+			Deadline:   r.Deadline,
+			Lead:       left,
+		},
+	})
+}
+
+type inAppReminderChannel struct {
+	notifications NotificationPublisher
+}
+
+// NewInAppReminderChannel puts reminders in the user's notification inbox.
+func NewInAppReminderChannel(notifications NotificationPublisher) ReminderChannel {
+	return &inAppReminderChannel{notifications}
+}
+
+func (ch *inAppReminderChannel) Name() string {
+	return dto.ReminderChannelInApp
+}
+
+func (ch *inAppReminderChannel) Send(ctx context.Context, r dto.Reminder) error {
+	left := min(r.Offset, time.Until(r.Deadline))
+	return ch.notifications.Publish(ctx, dto.Notification{
+		UserID:   r.UserID,
+		Type:     dto.NotificationDeadlineApproaching,
+		Title:    mailer.DueIn(r.TaskTitle, left, r.Locale),
+		Body:     fmt.Sprintf("%s · due %s", r.CourseName, r.Deadline.Format("Mon, 02 Jan 2006 15:04 MST")),
+		CourseID: r.CourseID,
+		TaskID:   r.TaskID,
+	})
+}
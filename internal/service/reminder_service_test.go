@@ -0,0 +1,41 @@
+package service
+
+import (
+	"courseworker/internal/db/sqlc"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestDueOffset(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	task := func(left time.Duration, defaults, typeOffsets string) sqlc.GetUpcomingDeadlinesRow {
+		return sqlc.GetUpcomingDeadlinesRow{
+			Deadline:       sql.NullTime{Time: now.Add(left), Valid: true},
+			DefaultOffsets: defaults,
+			TypeOffsets:    typeOffsets,
+		}
+	}
+
+	tests := []struct {
+		name string
+		task sqlc.GetUpcomingDeadlinesRow
+		want time.Duration
+		ok   bool
+	}{
+		{"nothing due yet", task(3*time.Hour, "120", ""), 0, false},
+		{"user default", task(90*time.Minute, "120", ""), 2 * time.Hour, true},
+		{"user default alongside type", task(90*time.Minute, "120", "4320,1440"), 2 * time.Hour, true},
+		{"type offset", task(20*time.Hour, "120", "4320,1440"), 24 * time.Hour, true},
+		{"shortest due offset wins", task(30*time.Minute, "60,1440", "120"), time.Hour, true},
+		{"type only", task(20*time.Hour, "", "1440"), 24 * time.Hour, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := dueOffset(tt.task, now)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("dueOffset = %v, %v; want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
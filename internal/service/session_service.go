@@ -0,0 +1,337 @@
+package service
+
+import (
+	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
+	"courseworker/internal/repository"
+	_error "courseworker/pkg/error"
+	_jwt "courseworker/pkg/jwt"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// A session is one login of a user on one device. It lives in Redis as a hash
+// under "session:<id>" and owns a single valid refresh token at a time; every
+// refresh rotates it. Hashes of rotated tokens are kept in "session-used:<id>"
+// so presenting one again is recognized as reuse and revokes every session of
+// the user. Revoking a session also denylists the access tokens issued for it.
+const (
+	sessionKeyPrefix      = "session:"
+	sessionUsedKeyPrefix  = "session-used:"
+	userSessionsKeyPrefix = "user-sessions:"
+)
+
+type SessionService interface {
+	CreateSession(c *gin.Context, userID string) (*dto.TokenResp, error)
+	Refresh(c *gin.Context, refreshToken string) (*dto.TokenResp, error)
+	GetSessions(c *gin.Context, userID, currentSessionID string) ([]dto.SessionResponse, error)
+	RevokeSession(c *gin.Context, userID, sessionID string) error
+	Logout(c *gin.Context, claims *dto.UserClaims) error
+	RevokeAll(c *gin.Context, userID string) error
+}
+
+type sessionService struct {
+	repo      repository.UserRepository
+	tokenRepo repository.PersonalAccessTokenRepository
+	rd        *redis.Client
+}
+
+func NewSessionService(r repository.UserRepository, tr repository.PersonalAccessTokenRepository, rdc *redis.Client) SessionService {
+	return &sessionService{
+		repo:      r,
+		tokenRepo: tr,
+		rd:        rdc,
+	}
+}
+
+func (s *sessionService) CreateSession(c *gin.Context, userID string) (*dto.TokenResp, error) {
+	const op _error.Op = "serv/CreateSession"
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get user"), err)
+	}
+	if user.DisabledAt.Valid {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Failed to create session"), "account has been disabled")
+	}
+
+	sessionID := uuid.New().String()
+	secret, err := generateSecretToken()
+	if err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to create session"), err)
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	ttl := _jwt.RefreshTokenExp()
+	key := sessionKeyPrefix + sessionID
+
+	pipe := s.rd.TxPipeline()
+	pipe.HSet(c, key, map[string]interface{}{
+		"user_id":      userID,
+		"token_hash":   hashSecretToken(secret),
+		"user_agent":   c.Request.UserAgent(),
+		"ip":           c.ClientIP(),
+		"created_at":   now,
+		"last_used_at": now,
+	})
+	pipe.Expire(c, key, ttl)
+	pipe.SAdd(c, userSessionsKeyPrefix+userID, sessionID)
+	if _, err := pipe.Exec(c); err != nil {
+		return nil, _error.E(op, _error.Cache, _error.Title("Failed to create session"), err)
+	}
+
+	return s.issueTokens(c, op, user, sessionID, secret)
+}
+
+// rotateRefreshScript swaps the session's refresh token hash atomically. It
+// returns 1 on success, 0 when the session is gone, -1 when the presented
+// token was already rotated out (reuse) and -2 when it is unknown.
+var rotateRefreshScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'token_hash')
+if not current then
+	return 0
+end
+if current ~= ARGV[1] then
+	if redis.call('SISMEMBER', KEYS[2], ARGV[1]) == 1 then
+		return -1
+	end
+	return -2
+end
+redis.call('HSET', KEYS[1], 'token_hash', ARGV[2], 'last_used_at', ARGV[3], 'ip', ARGV[4], 'user_agent', ARGV[5])
+redis.call('SADD', KEYS[2], ARGV[1])
+redis.call('EXPIRE', KEYS[1], ARGV[6])
+redis.call('EXPIRE', KEYS[2], ARGV[6])
+return 1
+`)
+
+func (s *sessionService) Refresh(c *gin.Context, refreshToken string) (*dto.TokenResp, error) {
+	const op _error.Op = "serv/Refresh"
+
+	sessionID, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || sessionID == "" || secret == "" {
+		return nil, _error.E(op, _error.Unauthorized, _error.Title("Failed to refresh token"), "invalid refresh token")
+	}
+	key := sessionKeyPrefix + sessionID
+	usedKey := sessionUsedKeyPrefix + sessionID
+
+	userID, err := s.rd.HGet(c, key, "user_id").Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, _error.E(op, _error.Unauthorized, _error.Title("Failed to refresh token"), "session has expired or been revoked")
+		}
+		return nil, _error.E(op, _error.Cache, _error.Title("Failed to refresh token"), err)
+	}
+
+	next, err := generateSecretToken()
+	if err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to refresh token"), err)
+	}
+	ttl := _jwt.RefreshTokenExp()
+
+	result, err := rotateRefreshScript.Run(c, s.rd, []string{key, usedKey},
+		hashSecretToken(secret),
+		hashSecretToken(next),
+		time.Now().Unix(),
+		c.ClientIP(),
+		c.Request.UserAgent(),
+		int64(ttl.Seconds()),
+	).Int()
+	if err != nil {
+		return nil, _error.E(op, _error.Cache, _error.Title("Failed to refresh token"), err)
+	}
+
+	switch result {
+	case 0:
+		return nil, _error.E(op, _error.Unauthorized, _error.Title("Failed to refresh token"), "session has expired or been revoked")
+	case -1:
+		// A rotated token showing up again means it leaked. There is no
+		// telling which other sessions the attacker reached, so the user is
+		// logged out everywhere.
+		log.Printf("Refresh token reuse detected on session %s, revoking all sessions of user %s", sessionID, userID)
+		if err := s.revokeSessions(c, userID); err != nil {
+			log.Printf("Failed to revoke sessions of user %s: %v", userID, err)
+		}
+		return nil, _error.E(op, _error.Unauthorized, _error.Title("Failed to refresh token"), "refresh token has already been used")
+	case -2:
+		return nil, _error.E(op, _error.Unauthorized, _error.Title("Failed to refresh token"), "invalid refresh token")
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get user"), err)
+	}
+	if user.DisabledAt.Valid {
+		if err := s.revoke(c, userID, sessionID); err != nil {
+			log.Printf("Failed to revoke session %s: %v", sessionID, err)
+		}
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Failed to refresh token"), "account has been disabled")
+	}
+
+	return s.issueTokens(c, op, user, sessionID, next)
+}
+
+func (s *sessionService) GetSessions(c *gin.Context, userID, currentSessionID string) ([]dto.SessionResponse, error) {
+	const op _error.Op = "serv/GetSessions"
+
+	setKey := userSessionsKeyPrefix + userID
+	ids, err := s.rd.SMembers(c, setKey).Result()
+	if err != nil {
+		return nil, _error.E(op, _error.Cache, _error.Title("Failed to get sessions"), err)
+	}
+
+	sessions := []dto.SessionResponse{}
+	for _, id := range ids {
+		values, err := s.rd.HGetAll(c, sessionKeyPrefix+id).Result()
+		if err != nil {
+			return nil, _error.E(op, _error.Cache, _error.Title("Failed to get sessions"), err)
+		}
+		if len(values) == 0 {
+			// The session expired on its own; drop the dangling reference.
+			if err := s.rd.SRem(c, setKey, id).Err(); err != nil {
+				log.Printf("Redis SRem failed: %v", err)
+			}
+			continue
+		}
+		sessions = append(sessions, dto.SessionResponse{
+			ID:         id,
+			UserAgent:  values["user_agent"],
+			IP:         values["ip"],
+			Current:    id == currentSessionID,
+			CreatedAt:  unixField(values["created_at"]),
+			LastUsedAt: unixField(values["last_used_at"]),
+		})
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+	return sessions, nil
+}
+
+func (s *sessionService) RevokeSession(c *gin.Context, userID, sessionID string) error {
+	const op _error.Op = "serv/RevokeSession"
+
+	owner, err := s.rd.HGet(c, sessionKeyPrefix+sessionID, "user_id").Result()
+	if err != nil && err != redis.Nil {
+		return _error.E(op, _error.Cache, _error.Title("Failed to revoke session"), err)
+	}
+	if err == redis.Nil || owner != userID {
+		return _error.E(
+			op, _error.NotExist, _error.Title("Session not found"),
+			"The requested session could not be found",
+		)
+	}
+
+	if err := s.revoke(c, userID, sessionID); err != nil {
+		return _error.E(op, _error.Cache, _error.Title("Failed to revoke session"), err)
+	}
+	return nil
+}
+
+// Logout ends the session the access token belongs to and denylists the
+// token itself so it stops working right away.
+func (s *sessionService) Logout(c *gin.Context, claims *dto.UserClaims) error {
+	const op _error.Op = "serv/Logout"
+
+	if err := _jwt.DenyToken(c, s.rd, claims); err != nil {
+		return _error.E(op, _error.Cache, _error.Title("Failed to log out"), err)
+	}
+	if claims.SessionID != "" {
+		if err := s.revoke(c, claims.ID, claims.SessionID); err != nil {
+			log.Printf("Failed to revoke session %s: %v", claims.SessionID, err)
+		}
+	}
+	return nil
+}
+
+// RevokeAll logs the user out everywhere: every issued access token is
+// invalidated, every session loses its refresh token and the personal access
+// tokens are deleted.
+func (s *sessionService) RevokeAll(c *gin.Context, userID string) error {
+	const op _error.Op = "serv/RevokeAll"
+
+	if err := s.revokeSessions(c, userID); err != nil {
+		return _error.E(op, _error.Cache, _error.Title("Failed to revoke sessions"), err)
+	}
+	if _, err := s.tokenRepo.DeletePersonalAccessTokensByUserID(userID); err != nil {
+		return _error.E(op, _error.Title("Failed to revoke sessions"), err)
+	}
+	return nil
+}
+
+// revokeSessions invalidates every access token issued to the user and
+// revokes all of their sessions.
+func (s *sessionService) revokeSessions(c *gin.Context, userID string) error {
+	if err := _jwt.BumpTokenVersion(c, s.rd, userID); err != nil {
+		return err
+	}
+	ids, err := s.rd.SMembers(c, userSessionsKeyPrefix+userID).Result()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := s.revoke(c, userID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revoke drops the session's refresh token and denylists the access tokens
+// already issued for it.
+func (s *sessionService) revoke(c *gin.Context, userID, sessionID string) error {
+	if err := _jwt.DenySession(c, s.rd, sessionID); err != nil {
+		return err
+	}
+	pipe := s.rd.TxPipeline()
+	pipe.Del(c, sessionKeyPrefix+sessionID, sessionUsedKeyPrefix+sessionID)
+	pipe.SRem(c, userSessionsKeyPrefix+userID, sessionID)
+	_, err := pipe.Exec(c)
+	return err
+}
+
+func (s *sessionService) issueTokens(c *gin.Context, op _error.Op, user *sqlc.User, sessionID, secret string) (*dto.TokenResp, error) {
+	version, err := _jwt.TokenVersion(c, s.rd, user.ID)
+	if err != nil {
+		return nil, _error.E(op, _error.Cache, _error.Title("Failed to generate token"), err)
+	}
+
+	token, err := _jwt.GenerateToken(*user, sessionID, version)
+	if err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to generate token"), err)
+	}
+	return &dto.TokenResp{
+		Token:        token,
+		RefreshToken: sessionID + "." + secret,
+		ExpiresIn:    int64(_jwt.AccessTokenExp().Seconds()),
+	}, nil
+}
+
+func generateSecretToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashSecretToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func unixField(value string) time.Time {
+	sec, _ := strconv.ParseInt(value, 10, 64)
+	return time.Unix(sec, 0)
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
+	"courseworker/internal/repository"
+	_error "courseworker/pkg/error"
+	"courseworker/pkg/storage"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+type TaskAttachmentService interface {
+	GetAttachments(c *gin.Context, authUserID, taskID string, courseID int64) ([]dto.TaskAttachmentResponse, error)
+	UploadAttachment(c *gin.Context, authUserID, taskID string, courseID int64, file *multipart.FileHeader) (*dto.TaskAttachmentResponse, error)
+	OpenAttachment(c *gin.Context, authUserID, taskID string, courseID, attachmentID int64) (*dto.TaskAttachmentResponse, *storage.Object, error)
+	DeleteAttachment(c *gin.Context, authUserID, taskID string, courseID, attachmentID int64) error
+	GetStorageUsage(c *gin.Context, authUserID string) (*dto.StorageUsageResponse, error)
+}
+
+const (
+	maxAttachmentSize   = 20 << 20
+	defaultStorageQuota = 100 << 20
+)
+
+// attachmentReservationPrefix keys the bytes of uploads still in flight per
+// user. They count against the quota until the attachment row exists, so
+// concurrent uploads cannot overshoot it.
+const attachmentReservationPrefix = "storage-reserved:"
+
+// oleContentType is what sniffContentType reports for the legacy Office
+// formats, which http.DetectContentType does not recognise.
+const oleContentType = "application/x-ole-storage"
+
+type attachmentType struct {
+	contentType string // what the file is stored and served with
+	sniffed     string // what sniffContentType must report for the content
+}
+
+// attachmentTypes maps the accepted attachment extensions to their content
+// type. The content is sniffed as well, so a file cannot pass as another type
+// by its name alone.
+var attachmentTypes = map[string]attachmentType{
+	".pdf":  {"application/pdf", "application/pdf"},
+	".doc":  {"application/msword", oleContentType},
+	".docx": {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", "application/zip"},
+	".ppt":  {"application/vnd.ms-powerpoint", oleContentType},
+	".pptx": {"application/vnd.openxmlformats-officedocument.presentationml.presentation", "application/zip"},
+	".xls":  {"application/vnd.ms-excel", oleContentType},
+	".xlsx": {"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "application/zip"},
+	".zip":  {"application/zip", "application/zip"},
+	".txt":  {"text/plain", "text/plain"},
+	".md":   {"text/markdown", "text/plain"},
+	".csv":  {"text/csv", "text/plain"},
+	".png":  {"image/png", "image/png"},
+	".jpg":  {"image/jpeg", "image/jpeg"},
+	".jpeg": {"image/jpeg", "image/jpeg"},
+}
+
+type taskAttachmentService struct {
+	repo repository.TaskAttachmentRepository
+	rd   *redis.Client
+	blob storage.Blob
+	ts   TaskService
+}
+
+func NewTaskAttachmentService(r repository.TaskAttachmentRepository, rdc *redis.Client, blob storage.Blob, taskServ TaskService) TaskAttachmentService {
+	return &taskAttachmentService{
+		repo: r,
+		rd:   rdc,
+		blob: blob,
+		ts:   taskServ,
+	}
+}
+
+func (s *taskAttachmentService) GetAttachments(c *gin.Context, authUserID, taskID string, courseID int64) ([]dto.TaskAttachmentResponse, error) {
+	const op _error.Op = "serv/GetAttachments"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Failed to get attachments"), err)
+	}
+
+	attachments, err := s.repo.GetAttachmentsByTaskIDs([]string{taskID})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get attachments"), err)
+	}
+	return dto.ToTaskAttachmentResponses(&attachments), nil
+}
+
+func (s *taskAttachmentService) UploadAttachment(c *gin.Context, authUserID, taskID string, courseID int64, file *multipart.FileHeader) (*dto.TaskAttachmentResponse, error) {
+	const op _error.Op = "serv/UploadAttachment"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
+	}
+
+	if file.Size > maxAttachmentSize {
+		return nil, _error.E(
+			op, _error.TooLarge, _error.Title("Failed to upload attachment"),
+			fmt.Sprintf("attachment must not be larger than %d MB", maxAttachmentSize>>20),
+		)
+	}
+	filename := filepath.Base(file.Filename)
+	typ, ok := attachmentTypes[strings.ToLower(filepath.Ext(filename))]
+	if !ok {
+		return nil, _error.E(
+			op, _error.InvalidRequest, _error.Title("Failed to upload attachment"),
+			fmt.Sprintf("unsupported file type %s", filepath.Ext(filename)),
+		)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to upload attachment"), err)
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to upload attachment"), err)
+	}
+	if sniffContentType(head[:n]) != typ.sniffed {
+		return nil, _error.E(
+			op, _error.InvalidRequest, _error.Title("Failed to upload attachment"),
+			fmt.Sprintf("file content does not match its %s extension", filepath.Ext(filename)),
+		)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to upload attachment"), err)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to upload attachment"), err)
+	}
+	checksum := hex.EncodeToString(h.Sum(nil))
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to upload attachment"), err)
+	}
+
+	// Blobs are content addressed per user, so the same handout attached to
+	// several tasks is stored and counted against the quota only once. The
+	// blob stays locked until the row exists, so a concurrent delete of the
+	// last other reference cannot remove the blob this row points at.
+	key := attachmentBlobPrefix(authUserID) + checksum
+	unlock, err := lockBlob(c, s.rd, key)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to upload attachment"), err)
+	}
+	defer unlock()
+
+	refs, err := s.repo.CountAttachmentsByBlobKey(key)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to upload attachment"), err)
+	}
+
+	if refs == 0 {
+		release, err := s.reserveStorage(c, authUserID, file.Size)
+		if err != nil {
+			return nil, _error.E(op, _error.Title("Failed to upload attachment"), err)
+		}
+		defer release()
+
+		if err := s.blob.Put(c, key, f, file.Size, typ.contentType); err != nil {
+			return nil, _error.E(op, _error.Internal, _error.Title("Failed to upload attachment"), err)
+		}
+	}
+
+	result, err := s.repo.CreateAttachment(sqlc.CreateAttachmentParams{
+		TaskID:      taskID,
+		UserID:      authUserID,
+		Filename:    filename,
+		ContentType: typ.contentType,
+		Size:        file.Size,
+		Checksum:    checksum,
+		BlobKey:     key,
+	})
+	if err != nil {
+		if refs == 0 {
+			deleteUnreferencedBlob(c, s.repo, s.blob, key)
+		}
+		return nil, _error.E(op, _error.Title("Failed to upload attachment"), err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get new id"), err)
+	}
+
+	attachment, err := s.repo.GetAttachmentByID(sqlc.GetAttachmentByIDParams{ID: id, TaskID: taskID})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get attachment"), err)
+	}
+	return dto.ToTaskAttachmentResponse(attachment), nil
+}
+
+func (s *taskAttachmentService) OpenAttachment(c *gin.Context, authUserID, taskID string, courseID, attachmentID int64) (*dto.TaskAttachmentResponse, *storage.Object, error) {
+	const op _error.Op = "serv/OpenAttachment"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, nil, _error.E(op, _error.Forbidden, _error.Title("Failed to get attachment"), err)
+	}
+
+	attachment, err := s.repo.GetAttachmentByID(sqlc.GetAttachmentByIDParams{ID: attachmentID, TaskID: taskID})
+	if err != nil {
+		return nil, nil, _error.E(op, _error.Title("Failed to get attachment"), err)
+	}
+
+	obj, err := s.blob.Open(c, attachment.BlobKey)
+	if err != nil {
+		return nil, nil, _error.E(op, _error.Internal, _error.Title("Failed to get attachment"), err)
+	}
+	return dto.ToTaskAttachmentResponse(attachment), obj, nil
+}
+
+func (s *taskAttachmentService) DeleteAttachment(c *gin.Context, authUserID, taskID string, courseID, attachmentID int64) error {
+	const op _error.Op = "serv/DeleteAttachment"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return _error.E(op, _error.Forbidden, _error.Title("Failed to delete attachment"), err)
+	}
+
+	attachment, err := s.repo.GetAttachmentByID(sqlc.GetAttachmentByIDParams{ID: attachmentID, TaskID: taskID})
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to delete attachment"), err)
+	}
+
+	if _, err := s.repo.DeleteAttachment(sqlc.DeleteAttachmentParams{ID: attachmentID, TaskID: taskID}); err != nil {
+		return _error.E(op, _error.Title("Failed to delete attachment"), err)
+	}
+
+	removeUnreferencedBlobs(c, s.rd, s.repo, s.blob, []string{attachment.BlobKey})
+	return nil
+}
+
+func (s *taskAttachmentService) GetStorageUsage(c *gin.Context, authUserID string) (*dto.StorageUsageResponse, error) {
+	const op _error.Op = "serv/GetStorageUsage"
+	used, err := s.repo.GetUserStorageUsage(authUserID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get storage usage"), err)
+	}
+	return &dto.StorageUsageResponse{Used: used, Quota: storageQuota()}, nil
+}
+
+// reserveStorage holds size bytes of the user's quota for an upload in flight
+// and fails when the stored attachments and the other reservations leave no
+// room for it. The reservation is taken before the usage is read, so of two
+// racing uploads the later one always counts the earlier one's bytes. Call
+// release once the attachment row exists or the upload failed; the key
+// expires on its own should the process die in between.
+func (s *taskAttachmentService) reserveStorage(c *gin.Context, authUserID string, size int64) (release func(), err error) {
+	const op _error.Op = "serv/reserveStorage"
+	key := attachmentReservationPrefix + authUserID
+
+	reserved, err := s.rd.IncrBy(c, key, size).Result()
+	if err != nil {
+		return nil, _error.E(op, _error.Cache, err)
+	}
+	release = func() {
+		if err := s.rd.DecrBy(context.Background(), key, size).Err(); err != nil {
+			log.Printf("Failed to release %d reserved bytes of %s: %v", size, authUserID, err)
+		}
+	}
+	if err := s.rd.Expire(c, key, 10*time.Minute).Err(); err != nil {
+		release()
+		return nil, _error.E(op, _error.Cache, err)
+	}
+
+	used, err := s.repo.GetUserStorageUsage(authUserID)
+	if err != nil {
+		release()
+		return nil, _error.E(op, err)
+	}
+	if used+reserved > storageQuota() {
+		release()
+		return nil, _error.E(op, _error.TooLarge, "storage quota exceeded")
+	}
+	return release, nil
+}
+
+// sniffContentType returns the media type of the file starting with head,
+// without parameters. Legacy Office files are reported as oleContentType.
+func sniffContentType(head []byte) string {
+	if bytes.HasPrefix(head, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}) {
+		return oleContentType
+	}
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	return mediaType
+}
+
+// storageQuota returns the per-user attachment quota in bytes, configured in
+// megabytes through ATTACHMENT_QUOTA_MB.
+func storageQuota() int64 {
+	mb, err := strconv.ParseInt(os.Getenv("ATTACHMENT_QUOTA_MB"), 10, 64)
+	if err != nil || mb <= 0 {
+		return defaultStorageQuota
+	}
+	return mb << 20
+}
+
+// attachmentBlobPrefix is the prefix under which all attachment blobs of the
+// user are stored.
+func attachmentBlobPrefix(userID string) string {
+	return "attachments/" + userID + "/"
+}
+
+// removeUnreferencedBlobs deletes the given attachment blobs once no
+// attachment row points at them anymore. Call it after the deletion of the
+// rows is committed. Failures are only logged since the rows are already gone
+// at this point.
+func removeUnreferencedBlobs(c *gin.Context, rd *redis.Client, repo repository.TaskAttachmentRepository, blob storage.Blob, keys []string) {
+	for _, key := range keys {
+		unlock, err := lockBlob(c, rd, key)
+		if err != nil {
+			log.Printf("Blob cleanup failed for %s: %v", key, err)
+			continue
+		}
+		deleteUnreferencedBlob(c, repo, blob, key)
+		unlock()
+	}
+}
+
+// deleteUnreferencedBlob deletes the blob under key unless an attachment row
+// points at it. The caller holds the blob's lock.
+func deleteUnreferencedBlob(c *gin.Context, repo repository.TaskAttachmentRepository, blob storage.Blob, key string) {
+	refs, err := repo.CountAttachmentsByBlobKey(key)
+	if err != nil {
+		log.Printf("Failed to count references of %s: %v", key, err)
+		return
+	}
+	if refs > 0 {
+		return
+	}
+	if err := blob.Delete(c, key); err != nil {
+		log.Printf("Blob cleanup failed for %s: %v", key, err)
+	}
+}
+
+// Attachment blobs are locked under "blob-lock:<key>" while an upload checks
+// and adds a reference or a cleanup checks the references and deletes the
+// blob. The lock expires on its own should the process die while holding it.
+const (
+	blobLockKeyPrefix = "blob-lock:"
+	blobLockTTL       = 2 * time.Minute
+	blobLockWait      = 30 * time.Second
+)
+
+// unlockBlob deletes the lock only while it is still the caller's, so a lock
+// that expired and was taken by another request is left alone.
+var unlockBlob = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// lockBlob waits up to blobLockWait for the lock of the blob under key.
+func lockBlob(c *gin.Context, rd *redis.Client, key string) (unlock func(), err error) {
+	const op _error.Op = "serv/lockBlob"
+	lockKey := blobLockKeyPrefix + key
+	token := uuid.New().String()
+
+	deadline := time.Now().Add(blobLockWait)
+	for {
+		locked, err := rd.SetNX(c, lockKey, token, blobLockTTL).Result()
+		if err != nil {
+			return nil, _error.E(op, _error.Cache, err)
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, _error.E(op, _error.Internal, "attachment storage is busy, please try again")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return func() {
+		if err := unlockBlob.Run(context.Background(), rd, []string{lockKey}, token).Err(); err != nil {
+			log.Printf("Failed to unlock %s: %v", lockKey, err)
+		}
+	}, nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
+	"courseworker/internal/repository"
+	_error "courseworker/pkg/error"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskChecklistService interface {
+	GetChecklist(c *gin.Context, authUserID, taskID string, courseID int64) ([]dto.ChecklistItemResponse, error)
+	CreateItem(c *gin.Context, authUserID, taskID string, courseID int64, req dto.ChecklistItemCreateReq) (*dto.ChecklistItemResponse, error)
+	UpdateItem(c *gin.Context, authUserID, taskID string, courseID, itemID int64, req dto.ChecklistItemUpdateReq) (*dto.ChecklistItemResponse, error)
+	DeleteItem(c *gin.Context, authUserID, taskID string, courseID, itemID int64) error
+	ReorderItems(c *gin.Context, authUserID, taskID string, courseID int64, req dto.ChecklistReorderReq) ([]dto.ChecklistItemResponse, error)
+}
+
+type taskChecklistService struct {
+	repo repository.TaskChecklistRepository
+	ts   TaskService
+}
+
+func NewTaskChecklistService(r repository.TaskChecklistRepository, taskServ TaskService) TaskChecklistService {
+	return &taskChecklistService{
+		repo: r,
+		ts:   taskServ,
+	}
+}
+
+func (s *taskChecklistService) GetChecklist(c *gin.Context, authUserID, taskID string, courseID int64) ([]dto.ChecklistItemResponse, error) {
+	const op _error.Op = "serv/GetChecklist"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Failed to get checklist"), err)
+	}
+
+	items, err := s.repo.GetItemsByTaskID(taskID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get checklist"), err)
+	}
+	return dto.ToChecklistItemResponses(&items), nil
+}
+
+func (s *taskChecklistService) CreateItem(c *gin.Context, authUserID, taskID string, courseID int64, req dto.ChecklistItemCreateReq) (*dto.ChecklistItemResponse, error) {
+	const op _error.Op = "serv/CreateChecklistItem"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
+	}
+
+	// New items are appended to the end of the list.
+	last, err := s.repo.GetMaxPosition(taskID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to create checklist item"), err)
+	}
+
+	result, err := s.repo.CreateItem(sqlc.CreateChecklistItemParams{
+		TaskID:   taskID,
+		Text:     req.Text,
+		Position: int32(last + 1),
+	})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to create checklist item"), err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get new id"), err)
+	}
+
+	item, err := s.repo.GetItemByID(sqlc.GetChecklistItemByIDParams{ID: id, TaskID: taskID})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get checklist item"), err)
+	}
+	return dto.ToChecklistItemResponse(item), nil
+}
+
+func (s *taskChecklistService) UpdateItem(c *gin.Context, authUserID, taskID string, courseID, itemID int64, req dto.ChecklistItemUpdateReq) (*dto.ChecklistItemResponse, error) {
+	const op _error.Op = "serv/UpdateChecklistItem"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
+	}
+
+	item, err := s.repo.GetItemByID(sqlc.GetChecklistItemByIDParams{ID: itemID, TaskID: taskID})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to update checklist item"), err)
+	}
+
+	param := sqlc.UpdateChecklistItemParams{
+		Text:   item.Text,
+		IsDone: item.IsDone,
+		ID:     itemID,
+		TaskID: taskID,
+	}
+	if req.Text != nil {
+		if *req.Text == "" {
+			return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to update checklist item"), "text must not be empty")
+		}
+		param.Text = *req.Text
+	}
+	if req.IsDone != nil {
+		param.IsDone = *req.IsDone
+	}
+
+	if _, err := s.repo.UpdateItem(param); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to update checklist item"), err)
+	}
+
+	item, err = s.repo.GetItemByID(sqlc.GetChecklistItemByIDParams{ID: itemID, TaskID: taskID})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get checklist item"), err)
+	}
+	return dto.ToChecklistItemResponse(item), nil
+}
+
+func (s *taskChecklistService) DeleteItem(c *gin.Context, authUserID, taskID string, courseID, itemID int64) error {
+	const op _error.Op = "serv/DeleteChecklistItem"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return _error.E(op, _error.Forbidden, _error.Title("Failed to delete checklist item"), err)
+	}
+
+	_, err := s.repo.DeleteItem(sqlc.DeleteChecklistItemParams{ID: itemID, TaskID: taskID})
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to delete checklist item"), err)
+	}
+	return nil
+}
+
+// ReorderItems rewrites the positions of a task's checklist to follow the
+// given order. The request must list every item of the checklist exactly once.
+func (s *taskChecklistService) ReorderItems(c *gin.Context, authUserID, taskID string, courseID int64, req dto.ChecklistReorderReq) ([]dto.ChecklistItemResponse, error) {
+	const op _error.Op = "serv/ReorderChecklistItems"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
+	}
+
+	items, err := s.repo.GetItemsByTaskID(taskID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to reorder checklist"), err)
+	}
+
+	remaining := make(map[int64]bool, len(items))
+	for _, i := range items {
+		remaining[i.ID] = true
+	}
+	for _, id := range req.ItemIDs {
+		if !remaining[id] {
+			return nil, _error.E(
+				op, _error.InvalidRequest, _error.Title("Failed to reorder checklist"),
+				"item_ids must contain every checklist item of the task exactly once",
+			)
+		}
+		delete(remaining, id)
+	}
+	if len(remaining) > 0 {
+		return nil, _error.E(
+			op, _error.InvalidRequest, _error.Title("Failed to reorder checklist"),
+			"item_ids must contain every checklist item of the task exactly once",
+		)
+	}
+
+	if err := s.repo.ReorderItems(taskID, req.ItemIDs); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to reorder checklist"), err)
+	}
+
+	items, err = s.repo.GetItemsByTaskID(taskID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get checklist"), err)
+	}
+	return dto.ToChecklistItemResponses(&items), nil
+}
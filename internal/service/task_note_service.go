@@ -0,0 +1,134 @@
+package service
+
+import (
+	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
+	"courseworker/internal/repository"
+	_error "courseworker/pkg/error"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskNoteService interface {
+	GetNotesOfTask(c *gin.Context, authUserID, taskID string, courseID int64, page, limit int) (*dto.TaskNotePage, error)
+	GetNoteByID(c *gin.Context, authUserID, taskID string, courseID, noteID int64) (*dto.TaskNoteResponse, error)
+	CreateNote(c *gin.Context, authUserID, taskID string, courseID int64, req dto.TaskNoteCreateUpdateReq) (*dto.ResponseID, error)
+	UpdateNote(c *gin.Context, authUserID, taskID string, courseID, noteID int64, req dto.TaskNoteCreateUpdateReq) (*dto.TaskNoteResponse, error)
+	DeleteNote(c *gin.Context, authUserID, taskID string, courseID, noteID int64) error
+}
+
+type taskNoteService struct {
+	repo repository.TaskNoteRepository
+	ts   TaskService
+}
+
+func NewTaskNoteService(r repository.TaskNoteRepository, taskServ TaskService) TaskNoteService {
+	return &taskNoteService{
+		repo: r,
+		ts:   taskServ,
+	}
+}
+
+func (s *taskNoteService) GetNotesOfTask(c *gin.Context, authUserID, taskID string, courseID int64, page, limit int) (*dto.TaskNotePage, error) {
+	const op _error.Op = "serv/GetNotesOfTask"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Failed to get notes"), err)
+	}
+
+	notes, err := s.repo.GetNotesByTaskID(sqlc.GetNotesByTaskIDParams{
+		TaskID: taskID,
+		Limit:  int32(limit),
+		Offset: int32((page - 1) * limit),
+	})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get notes"), err)
+	}
+
+	total, err := s.repo.CountNotesByTaskID(taskID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get notes"), err)
+	}
+
+	return &dto.TaskNotePage{
+		Notes: dto.ToTaskNoteResponses(&notes),
+		Page:  dto.PageMeta{Page: page, Limit: limit, Total: total},
+	}, nil
+}
+
+func (s *taskNoteService) GetNoteByID(c *gin.Context, authUserID, taskID string, courseID, noteID int64) (*dto.TaskNoteResponse, error) {
+	const op _error.Op = "serv/GetNoteByID"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Failed to get note"), err)
+	}
+
+	note, err := s.repo.GetNoteByID(sqlc.GetNoteByIDParams{ID: noteID, TaskID: taskID})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get note"), err)
+	}
+	return dto.ToTaskNoteResponse(note), nil
+}
+
+func (s *taskNoteService) CreateNote(c *gin.Context, authUserID, taskID string, courseID int64, req dto.TaskNoteCreateUpdateReq) (*dto.ResponseID, error) {
+	const op _error.Op = "serv/CreateNote"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
+	}
+
+	result, err := s.repo.CreateNote(sqlc.CreateNoteParams{
+		TaskID: taskID,
+		Text:   req.Text,
+	})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to create note"), err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get new id"), err)
+	}
+	return &dto.ResponseID{ID: id}, nil
+}
+
+func (s *taskNoteService) UpdateNote(c *gin.Context, authUserID, taskID string, courseID, noteID int64, req dto.TaskNoteCreateUpdateReq) (*dto.TaskNoteResponse, error) {
+	const op _error.Op = "serv/UpdateNote"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
+	}
+
+	if _, err := s.repo.GetNoteByID(sqlc.GetNoteByIDParams{ID: noteID, TaskID: taskID}); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to update note"), err)
+	}
+
+	_, err := s.repo.UpdateNote(sqlc.UpdateNoteParams{
+		Text:   req.Text,
+		ID:     noteID,
+		TaskID: taskID,
+	})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to update note"), err)
+	}
+
+	note, err := s.repo.GetNoteByID(sqlc.GetNoteByIDParams{ID: noteID, TaskID: taskID})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get note"), err)
+	}
+	return dto.ToTaskNoteResponse(note), nil
+}
+
+func (s *taskNoteService) DeleteNote(c *gin.Context, authUserID, taskID string, courseID, noteID int64) error {
+	const op _error.Op = "serv/DeleteNote"
+
+	if err := s.ts.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return _error.E(op, _error.Forbidden, _error.Title("Failed to delete note"), err)
+	}
+
+	_, err := s.repo.DeleteNote(sqlc.DeleteNoteParams{ID: noteID, TaskID: taskID})
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to delete note"), err)
+	}
+	return nil
+}
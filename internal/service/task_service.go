@@ -5,9 +5,16 @@ import (
 	"courseworker/internal/dto"
 	"courseworker/internal/repository"
 	_error "courseworker/pkg/error"
+	"courseworker/pkg/recurrence"
+	"courseworker/pkg/storage"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"mime/multipart"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -16,52 +23,224 @@ import (
 )
 
 type TaskService interface {
-	GetAllTasksOfUser(authUserID string) ([]dto.TaskResponse, error)
-	GetTasksByCourseID(c *gin.Context, authUserID string, courseID int64) ([]dto.TaskResponse, error)
-	GetTaskByID(c *gin.Context, authUserID, taskID string, courseID int64) (*dto.TaskResponse, error)
+	GetAllTasksOfUser(c *gin.Context, authUserID string, query dto.TaskListQuery) ([]dto.TaskResponse, *dto.ResponseMeta, error)
+	GetTasksByCourseID(c *gin.Context, authUserID string, courseID int64, query dto.TaskListQuery) ([]dto.TaskResponse, *dto.ResponseMeta, error)
+	GetTaskByID(c *gin.Context, authUserID, taskID string, courseID int64, includeNotes bool) (*dto.TaskResponse, error)
 	CreateTask(c *gin.Context, authUserID string, courseID int64, req dto.TaskCreateReq) (*dto.ResponseID, error)
-	DeleteTask(c *gin.Context, authUserID, taskID string, courseID int64) error
+	UpdateTask(c *gin.Context, authUserID, taskID string, courseID int64, req dto.TaskUpdateReq, scope string) (*dto.TaskResponse, error)
+	PatchTask(c *gin.Context, authUserID, taskID string, courseID int64, req dto.TaskPatchReq, scope string) (*dto.TaskResponse, error)
+	DeleteTask(c *gin.Context, authUserID, taskID string, courseID int64, scope string) error
 	SwitchTaskHighlight(c *gin.Context, authUserID, taskID string, courseID int64) (*dto.ResponseID, error)
+	SetTaskDone(c *gin.Context, authUserID, taskID string, courseID int64, done bool) (*dto.ResponseID, error)
+	UploadTaskImage(c *gin.Context, authUserID, taskID string, courseID int64, file *multipart.FileHeader) (*dto.TaskResponse, error)
+	RemoveTaskImage(c *gin.Context, authUserID, taskID string, courseID int64) (*dto.ResponseID, error)
+	ValidateOwnershipTask(c *gin.Context, authUserID, taskID string, courseID int64) error
+}
+
+const (
+	maxTaskImageSize = 5 << 20
+	imageURLTTL      = 15 * time.Minute
+)
+
+// taskImageTypes maps the accepted image MIME types to the file extension
+// used for the stored blob.
+var taskImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
 }
 
 type taskService struct {
-	repo repository.TaskRepository
-	rd   *redis.Client
-	cs   CourseService
+	repo       repository.TaskRepository
+	noteRepo   repository.TaskNoteRepository
+	attachRepo repository.TaskAttachmentRepository
+	checkRepo  repository.TaskChecklistRepository
+	rd         *redis.Client
+	blob       storage.Blob
+	cs         CourseService
+	tts        TaskTypeService
+	us         UserService
+	rs         ReminderService
+	ns         NotificationPublisher
 }
 
-func NewTaskService(r repository.TaskRepository, rdc *redis.Client, courseServ CourseService) TaskService {
+func NewTaskService(r repository.TaskRepository, nr repository.TaskNoteRepository, ar repository.TaskAttachmentRepository, clr repository.TaskChecklistRepository, rdc *redis.Client, blob storage.Blob, courseServ CourseService, taskTypeServ TaskTypeService, userServ UserService, reminderServ ReminderService, notifications NotificationPublisher) TaskService {
 	return &taskService{
-		repo: r,
-		rd:   rdc,
-		cs:   courseServ,
+		repo:       r,
+		noteRepo:   nr,
+		attachRepo: ar,
+		checkRepo:  clr,
+		rd:         rdc,
+		blob:       blob,
+		cs:         courseServ,
+		tts:        taskTypeServ,
+		us:         userServ,
+		rs:         reminderServ,
+		ns:         notifications,
 	}
 }
 
-func (s *taskService) GetAllTasksOfUser(authUserID string) ([]dto.TaskResponse, error) {
+func (s *taskService) GetAllTasksOfUser(c *gin.Context, authUserID string, query dto.TaskListQuery) ([]dto.TaskResponse, *dto.ResponseMeta, error) {
 	const op _error.Op = "serv/GetAllTasksOfUser"
-	tasks, err := s.repo.GetAllTasks(authUserID)
+
+	loc, err := s.us.GetUserLocation(c, authUserID)
 	if err != nil {
-		return nil, _error.E(op, _error.Title("Failed to get tasks"), err)
+		return nil, nil, _error.E(op, err)
 	}
-	return dto.ToTaskResponses(&tasks), nil
+	param, err := toListTasksParams(authUserID, query, loc)
+	if err != nil {
+		return nil, nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to get tasks"), err)
+	}
+	return s.listTasks(c, op, param)
 }
 
-func (s *taskService) GetTasksByCourseID(c *gin.Context, authUserID string, courseID int64) ([]dto.TaskResponse, error) {
+func (s *taskService) GetTasksByCourseID(c *gin.Context, authUserID string, courseID int64, query dto.TaskListQuery) ([]dto.TaskResponse, *dto.ResponseMeta, error) {
 	const op _error.Op = "serv/GetTasksByCourseID"
 
 	if err := s.cs.ValidateOwnershipCourse(c, authUserID, courseID); err != nil {
-		return nil, _error.E(op, _error.Forbidden, _error.Title("Failed to get tasks"), err)
+		return nil, nil, _error.E(op, _error.Forbidden, _error.Title("Failed to get tasks"), err)
+	}
+
+	loc, err := s.us.GetUserLocation(c, authUserID)
+	if err != nil {
+		return nil, nil, _error.E(op, err)
+	}
+	param, err := toListTasksParams(authUserID, query, loc)
+	if err != nil {
+		return nil, nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to get tasks"), err)
+	}
+	param.CourseID = sql.NullInt64{Int64: courseID, Valid: true}
+	return s.listTasks(c, op, param)
+}
+
+// listTasks fetches one page plus a single extra row; the extra row only
+// tells whether a next page exists and is never returned.
+func (s *taskService) listTasks(c *gin.Context, op _error.Op, param repository.ListTasksParams) ([]dto.TaskResponse, *dto.ResponseMeta, error) {
+	limit := param.Limit
+	param.Limit++
+
+	tasks, err := s.repo.ListTasks(param)
+	if err != nil {
+		return nil, nil, _error.E(op, _error.Title("Failed to get tasks"), err)
+	}
+
+	meta := &dto.ResponseMeta{}
+	if int32(len(tasks)) > limit {
+		tasks = tasks[:limit]
+		cursor := encodeTaskCursor(param.Sort, tasks[len(tasks)-1])
+		meta.NextCursor = &cursor
 	}
 
-	tasks, err := s.repo.GetTasksByCourse(courseID)
+	resps, err := s.toTaskResponses(c, param.UserID, tasks)
 	if err != nil {
-		return nil, _error.E(op, _error.Title("Failed to get tasks"), err)
+		return nil, nil, _error.E(op, err)
 	}
-	return dto.ToTaskResponses(&tasks), nil
+	return resps, meta, nil
 }
 
-func (s *taskService) GetTaskByID(c *gin.Context, authUserID, taskID string, courseID int64) (*dto.TaskResponse, error) {
+const defaultTaskPageLimit = 20
+
+func toListTasksParams(authUserID string, query dto.TaskListQuery, loc *time.Location) (repository.ListTasksParams, error) {
+	param := repository.ListTasksParams{
+		UserID: authUserID,
+		Status: query.Status,
+		Sort:   query.Sort,
+		Desc:   query.Order == "desc",
+		Limit:  int32(query.Limit),
+	}
+	if param.Sort == "" {
+		param.Sort = repository.TaskSortDeadline
+	}
+	if param.Limit == 0 {
+		param.Limit = defaultTaskPageLimit
+	}
+	if query.Type != "" {
+		param.Type = sql.NullString{String: query.Type, Valid: true}
+	}
+	if query.Highlight != nil {
+		param.Highlight = sql.NullBool{Bool: *query.Highlight, Valid: true}
+	}
+	if query.Q != "" {
+		param.Title = sql.NullString{String: query.Q, Valid: true}
+	}
+	if query.DeadlineFrom != "" {
+		from, err := parseDeadline(query.DeadlineFrom, loc)
+		if err != nil {
+			return param, fmt.Errorf("invalid deadline_from: %w", err)
+		}
+		param.DeadlineFrom = sql.NullTime{Time: from, Valid: true}
+	}
+	if query.DeadlineTo != "" {
+		to, err := parseDeadline(query.DeadlineTo, loc)
+		if err != nil {
+			return param, fmt.Errorf("invalid deadline_to: %w", err)
+		}
+		param.DeadlineTo = sql.NullTime{Time: to, Valid: true}
+	}
+	if query.Cursor != "" {
+		after, err := decodeTaskCursor(param.Sort, query.Cursor)
+		if err != nil {
+			return param, err
+		}
+		param.After = after
+	}
+	return param, nil
+}
+
+// taskCursor is the decoded form of the opaque next_cursor value. It records
+// the sort it was issued for so it cannot be replayed against another order.
+type taskCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// noDeadline mirrors the value the listing query sorts tasks without a
+// deadline by.
+var noDeadline = time.Date(2038, 1, 19, 3, 14, 7, 0, time.UTC)
+
+func encodeTaskCursor(sort string, t sqlc.Task) string {
+	cursor := taskCursor{Sort: sort, ID: t.ID}
+	switch sort {
+	case repository.TaskSortTitle:
+		cursor.Value = t.Title
+	case repository.TaskSortCreatedAt:
+		cursor.Value = t.CreatedAt.Format(time.RFC3339Nano)
+	default:
+		deadline := noDeadline
+		if t.Deadline.Valid {
+			deadline = t.Deadline.Time
+		}
+		cursor.Value = deadline.Format(time.RFC3339Nano)
+	}
+	b, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeTaskCursor(sort, value string) (*repository.TaskListCursor, error) {
+	errInvalid := errors.New("invalid cursor")
+
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errInvalid
+	}
+	var cursor taskCursor
+	if err := json.Unmarshal(b, &cursor); err != nil || cursor.Sort != sort {
+		return nil, errInvalid
+	}
+
+	if sort == repository.TaskSortTitle {
+		return &repository.TaskListCursor{Value: cursor.Value, ID: cursor.ID}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+	if err != nil {
+		return nil, errInvalid
+	}
+	return &repository.TaskListCursor{Value: t, ID: cursor.ID}, nil
+}
+
+func (s *taskService) GetTaskByID(c *gin.Context, authUserID, taskID string, courseID int64, includeNotes bool) (*dto.TaskResponse, error) {
 	const op _error.Op = "serv/GetTaskByID"
 
 	if err := s.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
@@ -72,7 +251,19 @@ func (s *taskService) GetTaskByID(c *gin.Context, authUserID, taskID string, cou
 	if err != nil {
 		return nil, _error.E(op, _error.Title("Failed to get task"), err)
 	}
-	return dto.ToTaskResponse(task), nil
+	resp, err := s.toTaskResponse(c, authUserID, task)
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+
+	if includeNotes {
+		notes, err := s.noteRepo.GetAllNotesByTaskID(taskID)
+		if err != nil {
+			return nil, _error.E(op, _error.Title("Failed to get notes"), err)
+		}
+		resp.Notes = dto.ToTaskNoteResponses(&notes)
+	}
+	return resp, nil
 }
 
 func (s *taskService) ValidateOwnershipTask(c *gin.Context, authUserID, taskID string, courseID int64) error {
@@ -111,47 +302,365 @@ func (s *taskService) CreateTask(c *gin.Context, authUserID string, courseID int
 		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
 	}
 
-	deadline, err := time.Parse("2006-01-02 15:04", req.Deadline)
+	if err := s.tts.ValidateTaskType(c, authUserID, req.Type); err != nil {
+		return nil, _error.E(op, err)
+	}
+
+	loc, err := s.us.GetUserLocation(c, authUserID)
 	if err != nil {
+		return nil, _error.E(op, err)
+	}
+	deadline, err := parseDeadline(req.Deadline, loc)
+	if err != nil {
+		return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to create task"), err)
+	}
+
+	// A recurring task is materialized up front as one task per occurrence,
+	// all linked to the same series.
+	deadlines := []time.Time{deadline}
+	var seriesID sql.NullString
+	var rrule string
+	if req.Recurrence != "" {
+		rule, err := recurrence.Parse(req.Recurrence)
+		if err != nil {
+			return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to create task"), err)
+		}
+		deadlines, err = rule.Occurrences(deadline)
+		if err != nil {
+			return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to create task"), err)
+		}
+		if len(deadlines) == 0 {
+			return nil, _error.E(
+				op, _error.InvalidRequest, _error.Title("Failed to create task"),
+				"recurrence does not produce any occurrence",
+			)
+		}
+
+		seriesID = sql.NullString{String: uuid.New().String(), Valid: true}
+		rrule = rule.String()
+	}
+
+	params := make([]sqlc.CreateTaskParams, len(deadlines))
+	for i, d := range deadlines {
+		params[i] = sqlc.CreateTaskParams{
+			ID:          uuid.New().String(),
+			CourseID:    courseID,
+			Title:       req.Title,
+			Type:        req.Type,
+			Description: sql.NullString{String: req.Description, Valid: true},
+			Deadline:    sql.NullTime{Time: d, Valid: true},
+			SeriesID:    seriesID,
+		}
+	}
+
+	if seriesID.Valid {
+		if err := s.repo.CreateTaskSeries(sqlc.CreateTaskSeriesParams{
+			ID:     seriesID.String,
+			UserID: authUserID,
+			Rrule:  rrule,
+		}, params); err != nil {
+			return nil, _error.E(op, _error.Title("Failed to create task"), err)
+		}
+	} else if _, err := s.repo.CreateTask(params[0]); err != nil {
 		return nil, _error.E(op, _error.Title("Failed to create task"), err)
 	}
-	param := sqlc.CreateTaskParams{
-		ID:          uuid.New().String(),
-		CourseID:    courseID,
+
+	created := make([]sqlc.Task, len(params))
+	for i, param := range params {
+		key := "task:" + param.ID
+		if err := s.rd.Set(c, key, authUserID, 0).Err(); err != nil {
+			log.Printf("Redis Set failed: %v", err)
+		}
+		created[i] = sqlc.Task{ID: param.ID, CourseID: param.CourseID, Title: param.Title, Deadline: param.Deadline}
+	}
+	s.notifyOverdue(c, authUserID, created)
+
+	return &dto.ResponseID{ID: params[0].ID}, nil
+}
+
+func (s *taskService) UpdateTask(c *gin.Context, authUserID, taskID string, courseID int64, req dto.TaskUpdateReq, scope string) (*dto.TaskResponse, error) {
+	const op _error.Op = "serv/UpdateTask"
+
+	if err := s.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
+	}
+
+	task, err := s.repo.GetTaskByID(taskID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get task"), err)
+	}
+
+	if err := s.tts.ValidateTaskType(c, authUserID, req.Type); err != nil {
+		return nil, _error.E(op, err)
+	}
+
+	loc, err := s.us.GetUserLocation(c, authUserID)
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+	deadline, err := parseDeadline(req.Deadline, loc)
+	if err != nil {
+		return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to update task"), err)
+	}
+
+	targetCourseID := courseID
+	if req.CourseID != 0 {
+		targetCourseID = req.CourseID
+	}
+
+	return s.saveTask(c, op, authUserID, courseID, task, sqlc.UpdateTaskParams{
+		CourseID:    targetCourseID,
 		Title:       req.Title,
 		Type:        req.Type,
 		Description: sql.NullString{String: req.Description, Valid: true},
 		Deadline:    sql.NullTime{Time: deadline, Valid: true},
+		ID:          taskID,
+	}, scope)
+}
+
+func (s *taskService) PatchTask(c *gin.Context, authUserID, taskID string, courseID int64, req dto.TaskPatchReq, scope string) (*dto.TaskResponse, error) {
+	const op _error.Op = "serv/PatchTask"
+
+	if err := s.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
 	}
-	_, err = s.repo.CreateTask(param)
+
+	task, err := s.repo.GetTaskByID(taskID)
 	if err != nil {
-		return nil, _error.E(op, _error.Title("Failed to create task"), err)
+		return nil, _error.E(op, _error.Title("Failed to get task"), err)
 	}
 
-	key := "task:" + authUserID
-	if err = s.rd.Set(c, key, authUserID, 0).Err(); err != nil {
-		log.Printf("Redis Set failed: %v", err)
+	param := sqlc.UpdateTaskParams{
+		CourseID:    task.CourseID,
+		Title:       task.Title,
+		Type:        task.Type,
+		Description: task.Description,
+		Deadline:    task.Deadline,
+		ID:          taskID,
+	}
+	if req.CourseID != nil {
+		param.CourseID = *req.CourseID
+	}
+	if req.Title != nil {
+		param.Title = *req.Title
+	}
+	if req.Type != nil {
+		if err := s.tts.ValidateTaskType(c, authUserID, *req.Type); err != nil {
+			return nil, _error.E(op, err)
+		}
+		param.Type = *req.Type
+	}
+	if req.Description != nil {
+		param.Description = sql.NullString{String: *req.Description, Valid: true}
+	}
+	if req.Deadline != nil {
+		loc, err := s.us.GetUserLocation(c, authUserID)
+		if err != nil {
+			return nil, _error.E(op, err)
+		}
+		deadline, err := parseDeadline(*req.Deadline, loc)
+		if err != nil {
+			return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to update task"), err)
+		}
+		param.Deadline = sql.NullTime{Time: deadline, Valid: true}
 	}
 
-	return &dto.ResponseID{ID: param.ID}, nil
+	return s.saveTask(c, op, authUserID, courseID, task, param, scope)
+}
+
+// saveTask writes the task and returns its fresh state. When the task is
+// being moved, the destination course has to belong to the user as well.
+// For a recurring task the scope decides whether the following or all
+// occurrences change too; their deadlines move by the same amount as the
+// edited one.
+func (s *taskService) saveTask(c *gin.Context, op _error.Op, authUserID string, courseID int64, task *sqlc.Task, param sqlc.UpdateTaskParams, scope string) (*dto.TaskResponse, error) {
+	if param.CourseID != courseID {
+		if err := s.cs.ValidateOwnershipCourse(c, authUserID, param.CourseID); err != nil {
+			return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
+		}
+	}
+
+	targets, err := s.scopedTasks(task, scope)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to update task"), err)
+	}
+
+	var shift time.Duration
+	if task.Deadline.Valid && param.Deadline.Valid {
+		shift = param.Deadline.Time.Sub(task.Deadline.Time)
+	}
+	var moved []sqlc.Task
+	for _, t := range targets {
+		p := param
+		if t.ID != task.ID {
+			p.ID = t.ID
+			p.Deadline = t.Deadline
+			if p.Deadline.Valid {
+				p.Deadline.Time = p.Deadline.Time.Add(shift)
+			}
+		}
+		if _, err := s.repo.UpdateTask(p); err != nil {
+			return nil, _error.E(op, _error.Title("Failed to update task"), err)
+		}
+		if p.Deadline.Valid != t.Deadline.Valid || !p.Deadline.Time.Equal(t.Deadline.Time) {
+			moved = append(moved, sqlc.Task{ID: p.ID, CourseID: p.CourseID, IsDone: t.IsDone, Title: p.Title, Deadline: p.Deadline})
+		}
+	}
+	s.notifyOverdue(c, authUserID, moved)
+
+	task, err = s.repo.GetTaskByID(param.ID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get task"), err)
+	}
+
+	resp, err := s.toTaskResponse(c, authUserID, task)
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+	return resp, nil
+}
+
+// notifyOverdue tells the user in the inbox about open tasks saved with a
+// deadline that has already passed. The reminder scheduler only looks back
+// overdueLookback and would be a tick late. The tasks are saved either way,
+// so failures are only logged.
+func (s *taskService) notifyOverdue(c *gin.Context, authUserID string, tasks []sqlc.Task) {
+	now := time.Now()
+	var overdue []sqlc.Task
+	for _, t := range tasks {
+		if !t.IsDone && t.Deadline.Valid && t.Deadline.Time.Before(now) {
+			overdue = append(overdue, t)
+		}
+	}
+	if len(overdue) == 0 {
+		return
+	}
+
+	prefs, err := s.rs.GetPreferences(c, authUserID)
+	if err != nil {
+		log.Printf("Failed to get reminder preferences of user %s: %v", authUserID, err)
+		return
+	}
+	inApp := false
+	for _, ch := range prefs.Channels {
+		inApp = inApp || ch == dto.ReminderChannelInApp
+	}
+	if !prefs.Enabled || !inApp {
+		return
+	}
+	loc, err := s.us.GetUserLocation(c, authUserID)
+	if err != nil {
+		log.Printf("Failed to get location of user %s: %v", authUserID, err)
+		loc = time.UTC
+	}
+
+	courseNames := map[int64]string{}
+	for _, t := range overdue {
+		name, ok := courseNames[t.CourseID]
+		if !ok {
+			course, err := s.cs.GetCourseByID(c, authUserID, t.CourseID)
+			if err != nil {
+				log.Printf("Failed to get course %d: %v", t.CourseID, err)
+				continue
+			}
+			name = course.Name
+			courseNames[t.CourseID] = name
+		}
+		if err := publishOverdue(c, s.rd, s.ns, dto.Notification{
+			UserID:   authUserID,
+			Type:     dto.NotificationTaskOverdue,
+			Title:    fmt.Sprintf("%s is overdue", t.Title),
+			Body:     fmt.Sprintf("%s · was due %s", name, t.Deadline.Time.In(loc).Format("Mon, 02 Jan 2006 15:04 MST")),
+			CourseID: t.CourseID,
+			TaskID:   t.ID,
+		}, t.Deadline.Time); err != nil {
+			log.Printf("Failed to publish overdue notification for task %s: %v", t.ID, err)
+		}
+	}
+}
+
+// scopedTasks returns the tasks an edit or delete of task applies to. Tasks
+// outside a series, and the "this" scope, only ever address the task itself.
+func (s *taskService) scopedTasks(task *sqlc.Task, scope string) ([]sqlc.Task, error) {
+	if !task.SeriesID.Valid || scope == "" || scope == dto.TaskScopeThis {
+		return []sqlc.Task{*task}, nil
+	}
+
+	series, err := s.repo.GetTasksBySeriesID(task.SeriesID.String)
+	if err != nil {
+		return nil, err
+	}
+	if scope == dto.TaskScopeAll {
+		return series, nil
+	}
+
+	targets := []sqlc.Task{}
+	for _, t := range series {
+		if t.ID == task.ID || !t.Deadline.Time.Before(task.Deadline.Time) {
+			targets = append(targets, t)
+		}
+	}
+	return targets, nil
 }
 
-func (s *taskService) DeleteTask(c *gin.Context, authUserID, taskID string, courseID int64) error {
+// parseDeadline accepts RFC3339 timestamps as well as the legacy
+// "2006-01-02 15:04" form, which is read as wall clock time in loc. The result
+// is always expressed in loc so that weekday based recurrences follow the
+// user's calendar rather than UTC.
+func parseDeadline(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(loc), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	if err != nil {
+		return time.Time{}, errors.New("deadline must be an RFC3339 timestamp or in the form YYYY-MM-DD HH:MM")
+	}
+	return t, nil
+}
+
+func (s *taskService) DeleteTask(c *gin.Context, authUserID, taskID string, courseID int64, scope string) error {
 	const op _error.Op = "serv/DeleteTask"
 
 	if err := s.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
 		return _error.E(op, _error.Forbidden, _error.Title("Failed to delete task"), err)
 	}
 
-	_, err := s.repo.DeleteTask(taskID)
+	task, err := s.repo.GetTaskByID(taskID)
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to get task"), err)
+	}
+	targets, err := s.scopedTasks(task, scope)
 	if err != nil {
 		return _error.E(op, _error.Title("Failed to delete task"), err)
 	}
+	taskIDs := make([]string, len(targets))
+	for i, t := range targets {
+		taskIDs[i] = t.ID
+	}
 
-	key := "task:" + taskID
-	if err = s.rd.Del(c, key).Err(); err != nil {
-		log.Printf("Redis Delete failed: %v", err)
+	attachments, err := s.attachRepo.GetAttachmentsByTaskIDs(taskIDs)
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to delete task"), err)
+	}
+
+	if err := s.repo.DeleteTasks(taskIDs, task.SeriesID); err != nil {
+		return _error.E(op, _error.Title("Failed to delete task"), err)
 	}
+	for _, id := range taskIDs {
+		key := "task:" + id
+		if err := s.rd.Del(c, key).Err(); err != nil {
+			log.Printf("Redis Delete failed: %v", err)
+		}
+
+		if err := s.blob.DeletePrefix(c, taskBlobPrefix(id)); err != nil {
+			log.Printf("Blob cleanup failed for task %s: %v", id, err)
+		}
+	}
+	blobKeys := make([]string, len(attachments))
+	for i, a := range attachments {
+		blobKeys[i] = a.BlobKey
+	}
+	removeUnreferencedBlobs(c, s.rd, s.attachRepo, s.blob, blobKeys)
 
 	return nil
 }
@@ -177,3 +686,194 @@ func (s *taskService) SwitchTaskHighlight(c *gin.Context, authUserID, taskID str
 	}
 	return &dto.ResponseID{ID: param.ID}, nil
 }
+
+func (s *taskService) SetTaskDone(c *gin.Context, authUserID, taskID string, courseID int64, done bool) (*dto.ResponseID, error) {
+	const op _error.Op = "serv/SetTaskDone"
+
+	if err := s.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
+	}
+
+	// Repeating the current state is a no-op so completed_at keeps the time
+	// the task was first completed.
+	task, err := s.repo.GetTaskByID(taskID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get task"), err)
+	}
+	if task.IsDone == done {
+		return &dto.ResponseID{ID: taskID}, nil
+	}
+
+	param := sqlc.SetTaskDoneParams{
+		IsDone: done,
+		ID:     taskID,
+	}
+	if done {
+		param.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+	if _, err := s.repo.SetTaskDone(param); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to update task"), err)
+	}
+	return &dto.ResponseID{ID: param.ID}, nil
+}
+
+func (s *taskService) UploadTaskImage(c *gin.Context, authUserID, taskID string, courseID int64, file *multipart.FileHeader) (*dto.TaskResponse, error) {
+	const op _error.Op = "serv/UploadTaskImage"
+
+	if err := s.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
+	}
+
+	if file.Size > maxTaskImageSize {
+		return nil, _error.E(
+			op, _error.InvalidRequest, _error.Title("Failed to upload image"),
+			fmt.Sprintf("image must not be larger than %d MB", maxTaskImageSize>>20),
+		)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to upload image"), err)
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, _ := f.Read(head)
+	contentType := http.DetectContentType(head[:n])
+	ext, ok := taskImageTypes[contentType]
+	if !ok {
+		return nil, _error.E(
+			op, _error.InvalidRequest, _error.Title("Failed to upload image"),
+			fmt.Sprintf("unsupported image type %s", contentType),
+		)
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to upload image"), err)
+	}
+
+	task, err := s.repo.GetTaskByID(taskID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get task"), err)
+	}
+
+	key := taskBlobPrefix(taskID) + "image-" + uuid.New().String() + ext
+	if err := s.blob.Put(c, key, f, file.Size, contentType); err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to upload image"), err)
+	}
+
+	if _, err := s.repo.AddImage(sqlc.AddImageParams{
+		Image: sql.NullString{String: key, Valid: true},
+		ID:    taskID,
+	}); err != nil {
+		if err := s.blob.Delete(c, key); err != nil {
+			log.Printf("Blob cleanup failed for %s: %v", key, err)
+		}
+		return nil, _error.E(op, _error.Title("Failed to upload image"), err)
+	}
+
+	if task.Image.Valid {
+		if err := s.blob.Delete(c, task.Image.String); err != nil {
+			log.Printf("Blob cleanup failed for %s: %v", task.Image.String, err)
+		}
+	}
+
+	task.Image = sql.NullString{String: key, Valid: true}
+	resp, err := s.toTaskResponse(c, authUserID, task)
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+	return resp, nil
+}
+
+func (s *taskService) RemoveTaskImage(c *gin.Context, authUserID, taskID string, courseID int64) (*dto.ResponseID, error) {
+	const op _error.Op = "serv/RemoveTaskImage"
+
+	if err := s.ValidateOwnershipTask(c, authUserID, taskID, courseID); err != nil {
+		return nil, _error.E(op, _error.Forbidden, _error.Title("Forbidden action"), err)
+	}
+
+	task, err := s.repo.GetTaskByID(taskID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get task"), err)
+	}
+	if !task.Image.Valid {
+		return &dto.ResponseID{ID: taskID}, nil
+	}
+
+	if _, err := s.repo.RemoveImage(taskID); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to remove image"), err)
+	}
+	if err := s.blob.Delete(c, task.Image.String); err != nil {
+		log.Printf("Blob cleanup failed for %s: %v", task.Image.String, err)
+	}
+	return &dto.ResponseID{ID: taskID}, nil
+}
+
+// toTaskResponse converts the task, swapping the stored image key for a
+// short-lived signed URL, attaching the attachment metadata and rendering its
+// timestamps in the user's timezone.
+func (s *taskService) toTaskResponse(c *gin.Context, authUserID string, task *sqlc.Task) (*dto.TaskResponse, error) {
+	resps, err := s.toTaskResponses(c, authUserID, []sqlc.Task{*task})
+	if err != nil {
+		return nil, err
+	}
+	return &resps[0], nil
+}
+
+func (s *taskService) toTaskResponses(c *gin.Context, authUserID string, tasks []sqlc.Task) ([]dto.TaskResponse, error) {
+	const op _error.Op = "serv/toTaskResponses"
+
+	loc, err := s.us.GetUserLocation(c, authUserID)
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+
+	taskIDs := make([]string, len(tasks))
+	for i, t := range tasks {
+		taskIDs[i] = t.ID
+	}
+	attachments, err := s.attachRepo.GetAttachmentsByTaskIDs(taskIDs)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get attachments"), err)
+	}
+	byTask := map[string][]sqlc.TaskAttachment{}
+	for _, a := range attachments {
+		byTask[a.TaskID] = append(byTask[a.TaskID], a)
+	}
+	progress, err := s.checkRepo.GetProgressByTaskIDs(taskIDs)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get checklist progress"), err)
+	}
+	progressByTask := map[string]sqlc.GetChecklistProgressByTaskIDsRow{}
+	for _, p := range progress {
+		progressByTask[p.TaskID] = p
+	}
+
+	resps := dto.ToTaskResponses(&tasks)
+	for i := range resps {
+		resps[i].Image = s.signImage(c, resps[i].Image)
+		taskAttachments := byTask[resps[i].ID]
+		resps[i].Attachments = dto.ToTaskAttachmentResponses(&taskAttachments)
+		p := progressByTask[resps[i].ID]
+		resps[i].Progress = dto.ChecklistProgress(p.Done, p.Total, resps[i].IsDone)
+		resps[i].InLocation(loc)
+	}
+	return resps, nil
+}
+
+func (s *taskService) signImage(c *gin.Context, key string) string {
+	if key == "" {
+		return ""
+	}
+	url, err := s.blob.SignedURL(c, key, imageURLTTL)
+	if err != nil {
+		log.Printf("Failed to sign url for %s: %v", key, err)
+		return ""
+	}
+	return url
+}
+
+// taskBlobPrefix is the storage prefix under which every file of a task lives.
+func taskBlobPrefix(taskID string) string {
+	return "tasks/" + taskID + "/"
+}
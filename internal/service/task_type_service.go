@@ -0,0 +1,161 @@
+package service
+
+import (
+	"courseworker/internal/db/sqlc"
+	"courseworker/internal/dto"
+	"courseworker/internal/repository"
+	_error "courseworker/pkg/error"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskTypeService interface {
+	GetTaskTypes(c *gin.Context, authUserID string) ([]dto.TaskTypeResponse, error)
+	CreateTaskType(c *gin.Context, authUserID string, req dto.TaskTypeCreateUpdateReq) (*dto.TaskTypeResponse, error)
+	UpdateTaskType(c *gin.Context, authUserID string, typeID int64, req dto.TaskTypeCreateUpdateReq) (*dto.TaskTypeResponse, error)
+	DeleteTaskType(c *gin.Context, authUserID string, typeID int64) error
+	ValidateTaskType(c *gin.Context, authUserID, name string) error
+}
+
+// defaultTaskTypes is the set every user starts with.
+var defaultTaskTypes = []dto.TaskTypeCreateUpdateReq{
+	{Name: "assignment", Color: "#2563EB", ReminderOffsets: []int{4320, 1440, 120}},
+	{Name: "quiz", Color: "#16A34A", ReminderOffsets: []int{1440, 120}},
+	{Name: "exam", Color: "#DC2626", ReminderOffsets: []int{10080, 4320, 1440}},
+	{Name: "group project", Color: "#9333EA", ReminderOffsets: []int{10080, 4320, 1440}},
+	{Name: "reading", Color: "#CA8A04", ReminderOffsets: []int{1440}},
+}
+
+type taskTypeService struct {
+	repo repository.TaskTypeRepository
+}
+
+func NewTaskTypeService(r repository.TaskTypeRepository) TaskTypeService {
+	return &taskTypeService{
+		repo: r,
+	}
+}
+
+func (s *taskTypeService) GetTaskTypes(c *gin.Context, authUserID string) ([]dto.TaskTypeResponse, error) {
+	const op _error.Op = "serv/GetTaskTypes"
+
+	types, err := s.repo.GetTaskTypesByUser(authUserID)
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get task types"), err)
+	}
+	return dto.ToTaskTypeResponses(&types), nil
+}
+
+func (s *taskTypeService) CreateTaskType(c *gin.Context, authUserID string, req dto.TaskTypeCreateUpdateReq) (*dto.TaskTypeResponse, error) {
+	const op _error.Op = "serv/CreateTaskType"
+
+	result, err := s.repo.CreateTaskType(sqlc.CreateTaskTypeParams{
+		UserID:          authUserID,
+		Name:            strings.TrimSpace(req.Name),
+		Color:           strings.ToUpper(req.Color),
+		ReminderOffsets: dto.FormatReminderOffsets(req.ReminderOffsets),
+	})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to create task type"), err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get new id"), err)
+	}
+
+	taskType, err := s.repo.GetTaskTypeByID(sqlc.GetTaskTypeByIDParams{ID: id, UserID: authUserID})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get task type"), err)
+	}
+	return dto.ToTaskTypeResponse(taskType), nil
+}
+
+func (s *taskTypeService) UpdateTaskType(c *gin.Context, authUserID string, typeID int64, req dto.TaskTypeCreateUpdateReq) (*dto.TaskTypeResponse, error) {
+	const op _error.Op = "serv/UpdateTaskType"
+
+	current, err := s.repo.GetTaskTypeByID(sqlc.GetTaskTypeByIDParams{ID: typeID, UserID: authUserID})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to update task type"), err)
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if _, err := s.repo.UpdateTaskType(sqlc.UpdateTaskTypeParams{
+		Name:            name,
+		Color:           strings.ToUpper(req.Color),
+		ReminderOffsets: dto.FormatReminderOffsets(req.ReminderOffsets),
+		ID:              typeID,
+		UserID:          authUserID,
+	}); err != nil {
+		return nil, _error.E(op, _error.Title("Failed to update task type"), err)
+	}
+
+	// Tasks reference their type by name, so a rename is carried over to them.
+	if name != current.Name {
+		if _, err := s.repo.RenameTypeOfTasks(sqlc.RenameTypeOfTasksParams{
+			NewType: name,
+			UserID:  authUserID,
+			OldType: current.Name,
+		}); err != nil {
+			return nil, _error.E(op, _error.Title("Failed to update task type"), err)
+		}
+	}
+
+	taskType, err := s.repo.GetTaskTypeByID(sqlc.GetTaskTypeByIDParams{ID: typeID, UserID: authUserID})
+	if err != nil {
+		return nil, _error.E(op, _error.Title("Failed to get task type"), err)
+	}
+	return dto.ToTaskTypeResponse(taskType), nil
+}
+
+func (s *taskTypeService) DeleteTaskType(c *gin.Context, authUserID string, typeID int64) error {
+	const op _error.Op = "serv/DeleteTaskType"
+
+	taskType, err := s.repo.GetTaskTypeByID(sqlc.GetTaskTypeByIDParams{ID: typeID, UserID: authUserID})
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to delete task type"), err)
+	}
+
+	count, err := s.repo.CountTasksOfType(sqlc.CountTasksOfTypeParams{UserID: authUserID, Type: taskType.Name})
+	if err != nil {
+		return _error.E(op, _error.Title("Failed to delete task type"), err)
+	}
+	if count > 0 {
+		return _error.E(
+			op, _error.InvalidRequest, _error.Title("Failed to delete task type"),
+			fmt.Sprintf("The task type %q is still used by %d task(s)", taskType.Name, count),
+		)
+	}
+
+	if _, err := s.repo.DeleteTaskType(sqlc.DeleteTaskTypeParams{ID: typeID, UserID: authUserID}); err != nil {
+		return _error.E(op, _error.Title("Failed to delete task type"), err)
+	}
+	return nil
+}
+
+func (s *taskTypeService) ValidateTaskType(c *gin.Context, authUserID, name string) error {
+	const op _error.Op = "serv/ValidateTaskType"
+
+	if _, err := s.repo.GetTaskTypeByName(sqlc.GetTaskTypeByNameParams{UserID: authUserID, Name: name}); err != nil {
+		return _error.E(op, _error.InvalidRequest, _error.Title("Invalid task type"), err)
+	}
+	return nil
+}
+
+// defaultTaskTypeParams returns the default task types of a new account.
+// They are created once, together with the user, so types the user deleted
+// stay deleted.
+func defaultTaskTypeParams(userID string) []sqlc.CreateTaskTypeParams {
+	params := make([]sqlc.CreateTaskTypeParams, 0, len(defaultTaskTypes))
+	for _, t := range defaultTaskTypes {
+		params = append(params, sqlc.CreateTaskTypeParams{
+			UserID:          userID,
+			Name:            t.Name,
+			Color:           t.Color,
+			ReminderOffsets: dto.FormatReminderOffsets(t.ReminderOffsets),
+		})
+	}
+	return params
+}
@@ -6,12 +6,19 @@ import (
 	"courseworker/internal/repository"
 	"courseworker/pkg/bcrypt"
 	_error "courseworker/pkg/error"
+	"courseworker/pkg/jobs"
 	_jwt "courseworker/pkg/jwt"
+	"courseworker/pkg/mailer"
+	"courseworker/pkg/ratelimit"
+	"courseworker/pkg/storage"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
@@ -19,76 +26,248 @@ import (
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
-	"gopkg.in/gomail.v2"
 )
 
 type UserService interface {
-	GetUsers() ([]dto.UserResponse, error)
-	GetUserByID(userID string) (*dto.UserResponse, error)
+	GetUsers(c *gin.Context, viewer *dto.UserClaims, query dto.UserListQuery) ([]dto.UserResponse, *dto.ResponseMeta, error)
+	GetUser(c *gin.Context, viewer *dto.UserClaims, userID string) (*dto.UserResponse, error)
+	GetUserByID(c *gin.Context, userID string) (*dto.UserResponse, error)
 	EmailExists(email string) (bool, error)
-	GenerateToken(email string) (*dto.TokenResp, error)
 	CreateUser(arg dto.CreateUserParams) (*dto.ResponseID, error)
 	HashPassword(pw string) (string, error)
 	SendConfirmationEmail(c *gin.Context, arg dto.CreateUserParams) (*dto.RegisterUserResp, error)
 	ValidateTokenAndClaims(c *gin.Context, reqToken string) (*dto.RegistrationClaims, error)
 	GetTempUser(c *gin.Context, tempUserID string) (*dto.CreateUserParams, error)
-	LoginUser(arg dto.LoginUserReq) (*dto.TokenResp, error)
+	LoginUser(c *gin.Context, arg dto.LoginUserReq) (*dto.TokenResp, error)
+	UpdateTimezone(c *gin.Context, userID string, req dto.UserTimezoneReq) (*dto.UserResponse, error)
+	GetUserLocation(c *gin.Context, userID string) (*time.Location, error)
+	ForgotPassword(c *gin.Context, req dto.ForgotPasswordReq) error
+	ResetPassword(c *gin.Context, req dto.ResetPasswordReq) error
 }
 
 type userService struct {
-	repo repository.UserRepository
-	rd   *redis.Client
+	repo    repository.UserRepository
+	rd      *redis.Client
+	blob    storage.Blob
+	ss      SessionService
+	mfa     MFAService
+	limiter *ratelimit.Limiter
+	queue   *jobs.Queue
+
+	// Emails sent to one address are throttled on top of the per-IP route
+	// limits, so the API cannot be used to flood somebody's inbox.
+	confirmationEmailRule  ratelimit.Rule
+	passwordResetEmailRule ratelimit.Rule
 }
 
-func NewUserService(r repository.UserRepository, rdc *redis.Client) UserService {
+func NewUserService(r repository.UserRepository, rdc *redis.Client, blob storage.Blob, sessionServ SessionService, mfaServ MFAService, queue *jobs.Queue) UserService {
 	return &userService{
-		repo: r,
-		rd:   rdc,
+		repo:    r,
+		rd:      rdc,
+		blob:    blob,
+		ss:      sessionServ,
+		mfa:     mfaServ,
+		limiter: ratelimit.New(rdc),
+		queue:   queue,
+
+		confirmationEmailRule:  ratelimit.FromEnv(ratelimit.Rule{Name: "confirmation-email", Limit: 3, Window: time.Hour}),
+		passwordResetEmailRule: ratelimit.FromEnv(ratelimit.Rule{Name: "password-reset-email", Limit: 3, Window: time.Hour}),
 	}
 }
 
-func (s *userService) GetUsers() ([]dto.UserResponse, error) {
+// GetUsers lists accounts for admins. Everyone else only ever sees their own
+// account, whatever the filters say.
+func (s *userService) GetUsers(c *gin.Context, viewer *dto.UserClaims, query dto.UserListQuery) ([]dto.UserResponse, *dto.ResponseMeta, error) {
 	const op _error.Op = "serv/GetUsers"
-	users, err := s.repo.GetAllUsers()
+
+	if viewer.Role != dto.RoleAdmin {
+		resp, err := s.GetUserByID(c, viewer.ID)
+		if err != nil {
+			return nil, nil, _error.E(op, err)
+		}
+		return []dto.UserResponse{*resp}, &dto.ResponseMeta{}, nil
+	}
+
+	param := repository.ListUsersParams{Limit: int32(query.Limit)}
+	if param.Limit == 0 {
+		param.Limit = defaultUserPageLimit
+	}
+	if query.Q != "" {
+		param.Query = sql.NullString{String: query.Q, Valid: true}
+	}
+	if query.Role != "" {
+		param.Role = sql.NullString{String: query.Role, Valid: true}
+	}
+	if query.Status != "" {
+		param.Disabled = sql.NullBool{Bool: query.Status == "disabled", Valid: true}
+	}
+	if query.Cursor != "" {
+		after, err := decodeUserCursor(query.Cursor)
+		if err != nil {
+			return nil, nil, _error.E(op, _error.InvalidRequest, _error.Title("Failed to get users"), err)
+		}
+		param.After = after
+	}
+
+	// One extra row tells whether another page follows.
+	limit := param.Limit
+	param.Limit++
+	users, err := s.repo.ListUsers(param)
+	if err != nil {
+		return nil, nil, _error.E(op, _error.Title("Failed to get users"), err)
+	}
+
+	meta := &dto.ResponseMeta{}
+	if int32(len(users)) > limit {
+		users = users[:limit]
+		cursor := encodeUserCursor(users[len(users)-1])
+		meta.NextCursor = &cursor
+	}
+
+	resps := dto.ToUserResponses(&users)
+	for i := range resps {
+		resps[i].ProfileImg, resps[i].Avatars = signAvatar(c, s.blob, resps[i].ProfileImg)
+	}
+	return resps, meta, nil
+}
+
+// GetUser returns the account if the viewer owns it or is an admin. Other
+// accounts are reported as missing so their IDs cannot be probed.
+func (s *userService) GetUser(c *gin.Context, viewer *dto.UserClaims, userID string) (*dto.UserResponse, error) {
+	const op _error.Op = "serv/GetUser"
+
+	if viewer.ID != userID && viewer.Role != dto.RoleAdmin {
+		return nil, _error.E(
+			op, _error.NotExist, _error.Title("User not found"),
+			"The requested user could not be found",
+		)
+	}
+	resp, err := s.GetUserByID(c, userID)
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+	return resp, nil
+}
+
+const defaultUserPageLimit = 20
+
+// userCursor is the decoded form of the user listing's next_cursor.
+type userCursor struct {
+	CreatedAt string `json:"c"`
+	ID        string `json:"id"`
+}
+
+func encodeUserCursor(u sqlc.User) string {
+	b, _ := json.Marshal(userCursor{
+		CreatedAt: u.CreatedAt.Time.Format(time.RFC3339Nano),
+		ID:        u.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeUserCursor(value string) (*repository.UserListCursor, error) {
+	errInvalid := errors.New("invalid cursor")
+
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errInvalid
+	}
+	var cursor userCursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return nil, errInvalid
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, cursor.CreatedAt)
 	if err != nil {
-		return nil, _error.E(op, _error.Title("Failed to get users"), err)
+		return nil, errInvalid
 	}
-	return dto.ToUserResponses(&users), nil
+	return &repository.UserListCursor{CreatedAt: createdAt, ID: cursor.ID}, nil
 }
 
-func (s *userService) GetUserByID(userID string) (*dto.UserResponse, error) {
+func (s *userService) GetUserByID(c *gin.Context, userID string) (*dto.UserResponse, error) {
 	const op _error.Op = "serv/GetUserByID"
 	user, err := s.repo.GetUserByID(userID)
 	if err != nil {
 		return nil, _error.E(op, _error.Title("Failed to get user"), err)
 	}
-	return dto.ToUserResponse(user), nil
+	resp := dto.ToUserResponse(user)
+	resp.ProfileImg, resp.Avatars = signAvatar(c, s.blob, resp.ProfileImg)
+	return resp, nil
 }
 
-func (s *userService) EmailExists(email string) (bool, error) {
-	const op _error.Op = "serv/EmailExists"
-	count, err := s.repo.EmailExists(email)
+func (s *userService) UpdateTimezone(c *gin.Context, userID string, req dto.UserTimezoneReq) (*dto.UserResponse, error) {
+	const op _error.Op = "serv/UpdateTimezone"
+
+	if !validTimezone(req.Timezone) {
+		return nil, _error.E(
+			op, _error.InvalidRequest, _error.Title("Failed to update timezone"),
+			fmt.Sprintf("%s is not a valid IANA timezone", req.Timezone),
+		)
+	}
+
+	_, err := s.repo.UpdateUserTimezone(sqlc.UpdateUserTimezoneParams{
+		Timezone: req.Timezone,
+		ID:       userID,
+	})
 	if err != nil {
-		return true, _error.E(op, _error.Title("Failed to check email existence"))
+		return nil, _error.E(op, _error.Title("Failed to update timezone"), err)
 	}
-	if count < 1 {
-		return false, nil
+
+	if err = s.rd.Set(c, userTimezoneKeyPrefix+userID, req.Timezone, 0).Err(); err != nil {
+		log.Printf("Redis Set failed: %v", err)
 	}
-	return true, nil
+
+	resp, err := s.GetUserByID(c, userID)
+	if err != nil {
+		return nil, _error.E(op, err)
+	}
+	return resp, nil
 }
 
-func (s *userService) GenerateToken(email string) (*dto.TokenResp, error) {
-	const op _error.Op = "serv/GenerateToken"
-	user, err := s.repo.GetUserByEmail(email)
+// userTimezoneKeyPrefix keys the cached IANA name of a user's timezone.
+const userTimezoneKeyPrefix = "user-tz:"
+
+// validTimezone reports whether name is an IANA zone the server can load.
+func validTimezone(name string) bool {
+	_, err := time.LoadLocation(name)
+	return err == nil && name != "Local"
+}
+
+// GetUserLocation returns the user's preferred timezone, falling back to UTC
+// when the stored zone cannot be loaded.
+func (s *userService) GetUserLocation(c *gin.Context, userID string) (*time.Location, error) {
+	const op _error.Op = "serv/GetUserLocation"
+
+	key := userTimezoneKeyPrefix + userID
+	name, err := s.rd.Get(c, key).Result()
 	if err != nil {
-		return nil, _error.E(op, _error.Title("Failed to get user"), err)
+		log.Printf("Redis Get failed: %v", err)
+		name, err = s.repo.GetUserTimezone(userID)
+		if err != nil {
+			return nil, _error.E(op, _error.Title("Failed to get user timezone"), err)
+		}
+		if err = s.rd.Set(c, key, name, 0).Err(); err != nil {
+			log.Printf("Redis Set failed: %v", err)
+		}
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("Unknown timezone %s for user %s: %v", name, userID, err)
+		return time.UTC, nil
 	}
+	return loc, nil
+}
 
-	token, err := _jwt.GenerateToken(*user)
+func (s *userService) EmailExists(email string) (bool, error) {
+	const op _error.Op = "serv/EmailExists"
+	count, err := s.repo.EmailExists(email)
 	if err != nil {
-		return nil, _error.E(op, _error.Internal, _error.Title("Failed to generate token"), err)
+		return true, _error.E(op, _error.Title("Failed to check email existence"))
+	}
+	if count < 1 {
+		return false, nil
 	}
-	return dto.ToTokenResp(token), nil
+	return true, nil
 }
 
 func (s *userService) CreateUser(arg dto.CreateUserParams) (*dto.ResponseID, error) {
@@ -99,10 +278,13 @@ func (s *userService) CreateUser(arg dto.CreateUserParams) (*dto.ResponseID, err
 		Name:     arg.Name,
 		Email:    arg.Email,
 		Password: arg.HashedPw,
+		Locale:   arg.Locale,
 	}
 
-	_, err := s.repo.CreateUser(input)
-	if err != nil {
+	if err := s.repo.CreateAccount(repository.CreateAccountParams{
+		User:      input,
+		TaskTypes: defaultTaskTypeParams(input.ID),
+	}); err != nil {
 		return nil, _error.E(op, _error.Title("Failed to create user"), err)
 	}
 	return &dto.ResponseID{ID: input.ID}, nil
@@ -120,6 +302,17 @@ func (s *userService) HashPassword(pw string) (string, error) {
 
 func (s *userService) SendConfirmationEmail(c *gin.Context, arg dto.CreateUserParams) (*dto.RegisterUserResp, error) {
 	const op _error.Op = "serv/SendConfirmationEmail"
+
+	allowed, wait, err := s.limiter.Allow(c, s.confirmationEmailRule, strings.ToLower(arg.Email))
+	if err != nil {
+		log.Printf("Rate limit check failed: %v", err)
+	} else if !allowed {
+		return nil, _error.E(
+			op, _error.TooManyRequests, _error.RetryAfter(wait),
+			_error.Title("Failed to send email"), "too many confirmation emails were sent to this address, please try again later",
+		)
+	}
+
 	domain := strings.Split(arg.Email, "@")[1]
 	mxRecords, err := net.LookupMX(domain)
 	if err != nil || len(mxRecords) == 0 {
@@ -132,6 +325,7 @@ func (s *userService) SendConfirmationEmail(c *gin.Context, arg dto.CreateUserPa
 		"name":      arg.Name,
 		"email":     arg.Email,
 		"hashed_pw": arg.HashedPw,
+		"locale":    requestLocale(c),
 	}).Err(); err != nil {
 		return nil, _error.E(op, _error.Cache, _error.Title("Failed to store data"), err)
 	}
@@ -145,36 +339,125 @@ func (s *userService) SendConfirmationEmail(c *gin.Context, arg dto.CreateUserPa
 	}
 
 	link := fmt.Sprintf("%s/account-confirm?token=%s", os.Getenv("BASE_URL"), token)
+	if err := sendEmail(c, s.queue, mailer.Email{
+		To:       arg.Email,
+		Template: mailer.AccountConfirm,
+		Locale:   requestLocale(c),
+		Data:     mailer.LinkData{Link: link},
+	}); err != nil {
+		return nil, _error.E(op, _error.Internal, _error.Title("Failed to send email"), err)
+	}
+
+	return &dto.RegisterUserResp{
+		Email: arg.Email,
+	}, nil
+}
 
-	smtpHost := os.Getenv("SMTP_HOST")
-	smtpPort := os.Getenv("SMTP_PORT")
-	smtpUser := os.Getenv("SMTP_USER")
-	smtpPass := os.Getenv("SMTP_PASS")
-	fromEmail := os.Getenv("FROM_EMAIL")
-	fromName := os.Getenv("FROM_NAME")
+const passwordResetTTL = 30 * time.Minute
 
-	m := gomail.NewMessage()
-	m.SetHeader("From", fmt.Sprintf("%s <%s>", fromName, fromEmail))
-	m.SetHeader("To", arg.Email)
-	m.SetHeader("Subject", "Email Confirmation")
-	m.SetBody("text/html", fmt.Sprintf("<p>Please confirm your email by clicking <a href='%s'>here</a>.</p>", link))
+// ForgotPassword mails a single-use reset link when the email belongs to an
+// account. It reports success either way so the endpoint cannot be used to
+// probe for registered addresses. Only the most recent link stays valid.
+func (s *userService) ForgotPassword(c *gin.Context, req dto.ForgotPasswordReq) error {
+	const op _error.Op = "serv/ForgotPassword"
 
-	port, err := strconv.Atoi(smtpPort)
+	user, err := s.repo.GetUserByEmail(req.Email)
 	if err != nil {
-		return nil, _error.E(op, _error.Internal, _error.Title("Failed to send email"), err)
+		var problem *_error.Problem
+		if errors.As(err, &problem) && problem.Kind == _error.NotExist {
+			return nil
+		}
+		return _error.E(op, _error.Title("Failed to request password reset"), err)
 	}
 
-	go func() {
-		d := gomail.NewDialer(smtpHost, port, smtpUser, smtpPass)
-		err = d.DialAndSend(m)
-		if err != nil {
-			log.Printf("Failed to send email to %s: %v", arg.Email, err)
+	// Rate limited requests look like successful ones, like unknown emails do.
+	allowed, _, err := s.limiter.Allow(c, s.passwordResetEmailRule, user.ID)
+	if err != nil {
+		log.Printf("Rate limit check failed: %v", err)
+	} else if !allowed {
+		return nil
+	}
+
+	token, err := generateSecretToken()
+	if err != nil {
+		return _error.E(op, _error.Internal, _error.Title("Failed to request password reset"), err)
+	}
+	hash := hashSecretToken(token)
+
+	userKey := "password-reset-user:" + user.ID
+	if previous, err := s.rd.Get(c, userKey).Result(); err == nil {
+		if err := s.rd.Del(c, "password-reset:"+previous).Err(); err != nil {
+			log.Printf("Redis Delete failed: %v", err)
 		}
-	}()
+	}
 
-	return &dto.RegisterUserResp{
-		Email: arg.Email,
-	}, nil
+	pipe := s.rd.TxPipeline()
+	pipe.Set(c, "password-reset:"+hash, user.ID, passwordResetTTL)
+	pipe.Set(c, userKey, hash, passwordResetTTL)
+	if _, err := pipe.Exec(c); err != nil {
+		return _error.E(op, _error.Cache, _error.Title("Failed to request password reset"), err)
+	}
+
+	// The link normally points at the frontend's reset form, which posts the
+	// token back to /password/reset.
+	resetURL := os.Getenv("PASSWORD_RESET_URL")
+	if resetURL == "" {
+		resetURL = os.Getenv("BASE_URL") + "/password/reset"
+	}
+	link := fmt.Sprintf("%s?token=%s", resetURL, token)
+	if err := sendEmail(c, s.queue, mailer.Email{
+		To:       user.Email,
+		Template: mailer.PasswordReset,
+		Locale:   requestLocale(c),
+		Data:     mailer.LinkData{Link: link, ExpiryMinutes: int(passwordResetTTL.Minutes())},
+	}); err != nil {
+		log.Printf("Failed to send password reset email: %v", err)
+	}
+	return nil
+}
+
+// ResetPassword consumes the reset token, stores the new password and logs
+// the user out of every session.
+func (s *userService) ResetPassword(c *gin.Context, req dto.ResetPasswordReq) error {
+	const op _error.Op = "serv/ResetPassword"
+
+	if req.Password != req.ConfirmPassword {
+		return _error.E(op, _error.InvalidRequest, _error.Title("Failed to reset password"), "password confirmation does not match")
+	}
+
+	userID, err := s.rd.GetDel(c, "password-reset:"+hashSecretToken(req.Token)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return _error.E(op, _error.InvalidRequest, _error.Title("Failed to reset password"), "reset token is invalid or has expired")
+		}
+		return _error.E(op, _error.Cache, _error.Title("Failed to reset password"), err)
+	}
+	if err := s.rd.Del(c, "password-reset-user:"+userID).Err(); err != nil {
+		log.Printf("Redis Delete failed: %v", err)
+	}
+
+	hashed, err := bcrypt.HashValue(req.Password)
+	if err != nil {
+		return _error.E(op, _error.Internal, _error.Title("Failed to reset password"), err)
+	}
+	if _, err := s.repo.UpdateUserPassword(sqlc.UpdateUserPasswordParams{
+		Password: hashed,
+		ID:       userID,
+	}); err != nil {
+		return _error.E(op, _error.Title("Failed to reset password"), err)
+	}
+
+	if err := s.ss.RevokeAll(c, userID); err != nil {
+		return _error.E(op, err)
+	}
+	// Whoever can read the mailbox owns the account, so the reset also lifts
+	// the login backoff someone else may have run it into.
+	if user, err := s.repo.GetUserByID(userID); err != nil {
+		log.Printf("Failed to get user %s: %v", userID, err)
+	} else {
+		s.clearLoginFailures(c, strings.ToLower(strings.TrimSpace(user.Email)))
+	}
+	return nil
 }
 
 func (s *userService) ValidateTokenAndClaims(c *gin.Context, reqToken string) (*dto.RegistrationClaims, error) {
@@ -201,24 +484,88 @@ func (s *userService) GetTempUser(c *gin.Context, tempUserID string) (*dto.Creat
 		Name:     result["name"],
 		Email:    result["email"],
 		HashedPw: result["hashed_pw"],
+		Locale:   mailer.MatchLocale(result["locale"]),
 	}, nil
 }
 
-func (s *userService) LoginUser(arg dto.LoginUserReq) (*dto.TokenResp, error) {
+// Failed logins are counted per account under "login-fail:<email>", so
+// guesses spread over many addresses add up. Once they reach
+// loginFailThreshold every further attempt has to wait out a backoff under
+// "login-backoff:<email>" that starts at loginBackoffBase and doubles with
+// each failure up to loginBackoffMax. Attempts during the backoff are refused
+// without counting, so an attacker cannot push it further than one failure
+// per backoff allows, and the owner gets a try whenever it has passed. A
+// successful login or a password reset clears both keys. The per-IP limit on
+// the route stays in front of this as a separate layer.
+const (
+	loginFailKeyPrefix    = "login-fail:"
+	loginBackoffKeyPrefix = "login-backoff:"
+	loginFailThreshold    = 5
+	loginFailWindow       = 24 * time.Hour
+	loginBackoffBase      = 30 * time.Second
+	loginBackoffMax       = 15 * time.Minute
+)
+
+func (s *userService) LoginUser(c *gin.Context, arg dto.LoginUserReq) (*dto.TokenResp, error) {
 	const op _error.Op = "serv/GetUserByEmail"
+	email := strings.ToLower(strings.TrimSpace(arg.Email))
+
+	if wait, err := s.rd.PTTL(c, loginBackoffKeyPrefix+email).Result(); err != nil {
+		log.Printf("Redis PTTL failed: %v", err)
+	} else if wait > 0 {
+		return nil, _error.E(
+			op, _error.TooManyRequests, _error.RetryAfter(wait),
+			_error.Title("Too many failed login attempts"), "too many failed login attempts for this account, please try again later",
+		)
+	}
+
 	user, err := s.repo.GetUserByEmail(arg.Email)
 	if err != nil {
+		if isKind(err, _error.NotExist) {
+			s.recordLoginFailure(c, email)
+		}
 		return nil, _error.E(op, _error.Title("Failed to get user"), err)
 	}
 
 	if err := bcrypt.ValidateHash(arg.Password, user.Password); err != nil {
+		s.recordLoginFailure(c, email)
 		return nil, _error.E(op, _error.Validation, _error.Title("Failed to validate password"), err)
 	}
 
-	token, err := _jwt.GenerateToken(*user)
+	s.clearLoginFailures(c, email)
+
+	token, err := s.mfa.CompleteLogin(c, user.ID)
 	if err != nil {
-		return nil, _error.E(op, _error.Internal, _error.Title("Failed to generate token"), err)
+		return nil, _error.E(op, err)
 	}
 
-	return dto.ToTokenResp(token), nil
+	return token, nil
+}
+
+func (s *userService) recordLoginFailure(c *gin.Context, email string) {
+	pipe := s.rd.TxPipeline()
+	incr := pipe.Incr(c, loginFailKeyPrefix+email)
+	pipe.Expire(c, loginFailKeyPrefix+email, loginFailWindow)
+	if _, err := pipe.Exec(c); err != nil {
+		log.Printf("Redis Incr failed: %v", err)
+		return
+	}
+
+	failures := incr.Val()
+	if failures < loginFailThreshold {
+		return
+	}
+	backoff := loginBackoffMax
+	if shift := failures - loginFailThreshold; shift < 8 {
+		backoff = min(loginBackoffBase<<shift, loginBackoffMax)
+	}
+	if err := s.rd.Set(c, loginBackoffKeyPrefix+email, failures, backoff).Err(); err != nil {
+		log.Printf("Redis Set failed: %v", err)
+	}
+}
+
+func (s *userService) clearLoginFailures(c *gin.Context, email string) {
+	if err := s.rd.Del(c, loginFailKeyPrefix+email, loginBackoffKeyPrefix+email).Err(); err != nil {
+		log.Printf("Redis Delete failed: %v", err)
+	}
 }
@@ -1,14 +1,29 @@
 package middleware
 
 import (
+	"courseworker/internal/dto"
+	_error "courseworker/pkg/error"
 	"courseworker/pkg/jwt"
+	"errors"
+	"log"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 )
 
-func ValidateToken() gin.HandlerFunc {
+// TokenAuthenticator resolves personal access tokens to the claims of their
+// owner.
+type TokenAuthenticator interface {
+	Authenticate(c *gin.Context, token string) (*dto.UserClaims, error)
+}
+
+// ValidateToken authenticates the request with either a session JWT or a
+// personal access token. Personal access tokens are only accepted when the
+// route names the scopes it needs and the token holds all of them; routes
+// without scopes are reserved for sessions.
+func ValidateToken(rd *redis.Client, pats TokenAuthenticator, scopes ...string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		header := ctx.Request.Header.Get("Authorization")
 
@@ -25,13 +40,82 @@ func ValidateToken() gin.HandlerFunc {
 
 		tokenString := tokenParts[1]
 
+		if strings.HasPrefix(tokenString, dto.AccessTokenPrefix) {
+			validateAccessToken(ctx, pats, tokenString, scopes)
+			return
+		}
+
 		claims, err := jwt.DecodeToken(tokenString)
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Failed Decode Token"})
 			return
 		}
 
+		revoked, err := jwt.IsRevoked(ctx, rd, claims)
+		if err != nil {
+			log.Printf("Failed to check token revocation: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"message": "Failed to verify token"})
+			return
+		}
+		if revoked {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Token has been revoked"})
+			return
+		}
+
 		ctx.Set("user", claims)
 		ctx.Next()
 	}
 }
+
+func validateAccessToken(ctx *gin.Context, pats TokenAuthenticator, token string, scopes []string) {
+	if pats == nil || len(scopes) == 0 {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "Access tokens cannot be used for this resource."})
+		return
+	}
+
+	claims, err := pats.Authenticate(ctx, token)
+	if err != nil {
+		var problem *_error.Problem
+		switch {
+		case errors.As(err, &problem) && problem.Kind == _error.Forbidden:
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "This account has been disabled."})
+		case errors.As(err, &problem) && problem.Kind == _error.Unauthorized:
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Invalid Token"})
+		default:
+			log.Printf("Failed to verify access token: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"message": "Failed to verify token"})
+		}
+		return
+	}
+
+	for _, scope := range scopes {
+		if !claims.HasScope(scope) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "Token is missing the " + scope + " scope."})
+			return
+		}
+	}
+
+	ctx.Set("user", claims)
+	ctx.Next()
+}
+
+// RequireRole only lets through users whose token carries one of the given
+// roles. It must run after ValidateToken.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		auth, _ := ctx.Get("user")
+		claims, ok := auth.(*dto.UserClaims)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "You must be logged in first."})
+			return
+		}
+
+		for _, role := range roles {
+			if claims.Role == role {
+				ctx.Next()
+				return
+			}
+		}
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "You are not allowed to access this resource."})
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	_error "courseworker/pkg/error"
+	"courseworker/pkg/ratelimit"
+	"courseworker/pkg/response"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimit throttles a route per client IP under the given rule. If Redis
+// is unavailable requests are let through rather than locking everyone out.
+func RateLimit(rd *redis.Client, rule ratelimit.Rule) gin.HandlerFunc {
+	const op _error.Op = "mw/RateLimit"
+	limiter := ratelimit.New(rd)
+
+	return func(ctx *gin.Context) {
+		allowed, wait, err := limiter.Allow(ctx, rule, ctx.ClientIP())
+		if err != nil {
+			log.Printf("Rate limit check failed: %v", err)
+			ctx.Next()
+			return
+		}
+		if !allowed {
+			response.HttpError(ctx, _error.E(
+				op, _error.TooManyRequests, _error.RetryAfter(wait),
+				_error.Title("Too many requests"), "too many requests, please try again later",
+			))
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"runtime"
 	"sort"
+	"time"
 )
 
 // Op represents the operation or function where an error occurred.
@@ -18,21 +19,28 @@ type Detail string
 // used in 'message' json response.
 type Title string
 
+// RetryAfter tells the client how long to wait before trying again.
+// used in the 'Retry-After' response header.
+type RetryAfter time.Duration
+
 // Kind represents the category of the error.
 // used in 'error.kind' json response.
 type Kind uint8
 
 // Error kinds representing various types of errors.
 const (
-	Other          Kind = iota // Unexpected error
-	InvalidRequest             // Error caused by an invalid request
-	Exist                      // Error when a resource already exists
-	NotExist                   // Error when a resource does not exist
-	Validation                 // Error caused by validation failure
-	Forbidden                  // Error due to insufficient permissions
-	Database                   // Database-related error
-	Internal                   // Internal server error
-	Cache                      // Cache-database-related error
+	Other           Kind = iota // Unexpected error
+	InvalidRequest              // Error caused by an invalid request
+	Exist                       // Error when a resource already exists
+	NotExist                    // Error when a resource does not exist
+	Validation                  // Error caused by validation failure
+	Forbidden                   // Error due to insufficient permissions
+	Database                    // Database-related error
+	Internal                    // Internal server error
+	Cache                       // Cache-database-related error
+	Unauthorized                // Error due to missing or invalid credentials
+	TooManyRequests             // Error when a rate limit or lockout applies
+	TooLarge                    // Error when an upload exceeds a size limit or quota
 )
 
 // String returns the string representation of an error Kind.
@@ -56,6 +64,12 @@ func (k Kind) String() string {
 		return "internal_server_error"
 	case Cache:
 		return "cache_error"
+	case Unauthorized:
+		return "unauthorized"
+	case TooManyRequests:
+		return "too_many_requests"
+	case TooLarge:
+		return "payload_too_large"
 	default:
 		return "unknown_error_kind"
 	}
@@ -79,6 +93,8 @@ type Problem struct {
 	Detail Detail // Additional details about the error
 	Params Params // Parameters associated with the error
 	Err    error  // Nested or wrapped error
+
+	RetryAfter RetryAfter // Wait before the request may be retried
 }
 
 // E constructs a new Problem instance with the provided arguments.
@@ -110,6 +126,8 @@ func E(args ...interface{}) error {
 			e.Title = arg
 		case Detail:
 			e.Detail = arg
+		case RetryAfter:
+			e.RetryAfter = arg
 		case string:
 			e.Err = errors.New(arg)
 		case *Problem:
@@ -133,6 +151,10 @@ func E(args ...interface{}) error {
 		prev.Kind = Other
 	}
 
+	if e.RetryAfter == 0 {
+		e.RetryAfter = prev.RetryAfter
+	}
+
 	if prev.Title == e.Title {
 		prev.Title = ""
 	}
@@ -0,0 +1,349 @@
+// Package jobs is a durable background job queue on Redis Streams.
+//
+// Jobs are appended to a stream and consumed through a consumer group, so
+// every job is handled by one worker across all replicas. A job stays pending
+// until its handler returns; jobs of a worker that died are claimed by the
+// others once they have been idle for ClaimIdle. Failed jobs are retried with
+// exponential backoff through a sorted set of delayed jobs and end up in a
+// dead-letter stream after MaxAttempts.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Job is the envelope stored in the stream.
+type Job struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempt    int             `json:"attempt"`
+	EnqueuedAt time.Time       `json:"enqueued_at"`
+	LastError  string          `json:"last_error,omitempty"`
+}
+
+// Handler processes the raw payload of one job type.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Type ties a job name to its payload type so producers and handlers cannot
+// disagree about the payload.
+type Type[T any] struct {
+	Name string
+}
+
+// Enqueue adds a job of this type to the queue.
+func (t Type[T]) Enqueue(ctx context.Context, q *Queue, payload T) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode %s payload: %w", t.Name, err)
+	}
+	return q.Enqueue(ctx, t.Name, raw)
+}
+
+// Handle registers fn for jobs of type t. Handlers must be registered before
+// Run is called.
+func Handle[T any](q *Queue, t Type[T], fn func(ctx context.Context, payload T) error) {
+	q.handlers[t.Name] = func(ctx context.Context, raw json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return Permanent(fmt.Errorf("decode %s payload: %w", t.Name, err))
+		}
+		return fn(ctx, payload)
+	}
+}
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error as not worth retrying; the job goes straight to
+// the dead-letter stream.
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+type Options struct {
+	Stream      string        // stream name, "jobs" by default
+	Group       string        // consumer group, "workers" by default
+	Consumer    string        // consumer name, host name and pid by default
+	Concurrency int           // jobs handled at once, 4 by default
+	MaxAttempts int           // attempts before dead-lettering, 5 by default
+	BaseBackoff time.Duration // wait before the first retry, 10s by default
+	MaxBackoff  time.Duration // longest wait between retries, 1h by default
+	JobTimeout  time.Duration // time a handler gets per attempt, 1m by default
+	ClaimIdle   time.Duration // idle time before another worker takes a job over, 5m by default
+}
+
+type Queue struct {
+	rd       *redis.Client
+	opts     Options
+	handlers map[string]Handler
+}
+
+func NewQueue(rd *redis.Client, opts Options) *Queue {
+	if opts.Stream == "" {
+		opts.Stream = "jobs"
+	}
+	if opts.Group == "" {
+		opts.Group = "workers"
+	}
+	if opts.Consumer == "" {
+		host, _ := os.Hostname()
+		opts.Consumer = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = 10 * time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Hour
+	}
+	if opts.JobTimeout <= 0 {
+		opts.JobTimeout = time.Minute
+	}
+	if opts.ClaimIdle <= 0 {
+		opts.ClaimIdle = 5 * time.Minute
+	}
+	return &Queue{
+		rd:       rd,
+		opts:     opts,
+		handlers: map[string]Handler{},
+	}
+}
+
+func (q *Queue) delayedKey() string { return q.opts.Stream + ":delayed" }
+
+// DeadLetterStream is where jobs go after their last failed attempt.
+func (q *Queue) DeadLetterStream() string { return q.opts.Stream + ":dead" }
+
+// Enqueue adds a job with an already encoded payload.
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload json.RawMessage) error {
+	job := Job{
+		ID:         uuid.New().String(),
+		Type:       jobType,
+		Payload:    payload,
+		EnqueuedAt: time.Now().UTC(),
+	}
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return q.rd.XAdd(ctx, &redis.XAddArgs{
+		Stream: q.opts.Stream,
+		Values: map[string]interface{}{"job": raw},
+	}).Err()
+}
+
+// Run consumes jobs until ctx is cancelled. It then stops taking new jobs,
+// lets the ones in progress finish and returns. Jobs read but not started
+// stay pending and are picked up again after ClaimIdle.
+func (q *Queue) Run(ctx context.Context) error {
+	if err := q.ensureGroup(ctx); err != nil {
+		return err
+	}
+
+	work := make(chan redis.XMessage)
+	var wg sync.WaitGroup
+	for i := 0; i < q.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for msg := range work {
+				q.process(msg)
+			}
+		}()
+	}
+	defer func() {
+		close(work)
+		wg.Wait()
+	}()
+
+	for ctx.Err() == nil {
+		msgs, err := q.fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			log.Printf("Job queue read failed: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		for _, msg := range msgs {
+			select {
+			case work <- msg:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (q *Queue) ensureGroup(ctx context.Context) error {
+	err := q.rd.XGroupCreateMkStream(ctx, q.opts.Stream, q.opts.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("create consumer group: %w", err)
+	}
+	return nil
+}
+
+// promoteDelayed moves retries whose backoff has passed back onto the
+// stream. Doing it in one script keeps two workers from promoting the same
+// job.
+var promoteDelayed = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, raw in ipairs(due) do
+	redis.call('ZREM', KEYS[1], raw)
+	redis.call('XADD', KEYS[2], '*', 'job', raw)
+end
+return #due
+`)
+
+const fetchBatch = 16
+
+// fetch returns the next jobs: stale ones of dead workers first, then new
+// ones, waiting up to two seconds for the latter.
+func (q *Queue) fetch(ctx context.Context) ([]redis.XMessage, error) {
+	now := time.Now().UnixMilli()
+	if err := promoteDelayed.Run(ctx, q.rd, []string{q.delayedKey(), q.opts.Stream}, now, fetchBatch).Err(); err != nil {
+		return nil, err
+	}
+
+	claimed, _, err := q.rd.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   q.opts.Stream,
+		Group:    q.opts.Group,
+		Consumer: q.opts.Consumer,
+		MinIdle:  q.opts.ClaimIdle,
+		Start:    "0-0",
+		Count:    fetchBatch,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(claimed) > 0 {
+		return claimed, nil
+	}
+
+	streams, err := q.rd.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    q.opts.Group,
+		Consumer: q.opts.Consumer,
+		Streams:  []string{q.opts.Stream, ">"},
+		Count:    fetchBatch,
+		Block:    2 * time.Second,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var msgs []redis.XMessage
+	for _, s := range streams {
+		msgs = append(msgs, s.Messages...)
+	}
+	return msgs, nil
+}
+
+// process runs one job. It deliberately does not use the Run context so a
+// shutdown lets the job finish.
+func (q *Queue) process(msg redis.XMessage) {
+	ctx := context.Background()
+
+	raw, _ := msg.Values["job"].(string)
+	var job Job
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		log.Printf("Dropping malformed job %s: %v", msg.ID, err)
+		q.finish(ctx, msg.ID, func(pipe redis.Pipeliner) {
+			pipe.XAdd(ctx, &redis.XAddArgs{Stream: q.DeadLetterStream(), Values: map[string]interface{}{"job": raw}})
+		})
+		return
+	}
+
+	err := q.run(job)
+	if err == nil {
+		q.finish(ctx, msg.ID, nil)
+		return
+	}
+
+	job.Attempt++
+	job.LastError = err.Error()
+	var permanent permanentError
+	if errors.As(err, &permanent) || job.Attempt >= q.opts.MaxAttempts {
+		log.Printf("Job %s (%s) failed for good after %d attempts: %v", job.ID, job.Type, job.Attempt, err)
+		encoded, _ := json.Marshal(job)
+		q.finish(ctx, msg.ID, func(pipe redis.Pipeliner) {
+			pipe.XAdd(ctx, &redis.XAddArgs{Stream: q.DeadLetterStream(), Values: map[string]interface{}{"job": encoded}})
+		})
+		return
+	}
+
+	wait := q.backoff(job.Attempt)
+	log.Printf("Job %s (%s) failed, retrying in %s: %v", job.ID, job.Type, wait, err)
+	encoded, _ := json.Marshal(job)
+	q.finish(ctx, msg.ID, func(pipe redis.Pipeliner) {
+		pipe.ZAdd(ctx, q.delayedKey(), redis.Z{
+			Score:  float64(time.Now().Add(wait).UnixMilli()),
+			Member: encoded,
+		})
+	})
+}
+
+func (q *Queue) run(job Job) (err error) {
+	handler, ok := q.handlers[job.Type]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler for job type %q", job.Type))
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), q.opts.JobTimeout)
+	defer cancel()
+	return handler(ctx, job.Payload)
+}
+
+// finish acknowledges and removes the message together with whatever
+// follow-up the caller queues, so a job is never both retried and lost.
+func (q *Queue) finish(ctx context.Context, id string, also func(pipe redis.Pipeliner)) {
+	pipe := q.rd.TxPipeline()
+	if also != nil {
+		also(pipe)
+	}
+	pipe.XAck(ctx, q.opts.Stream, q.opts.Group, id)
+	pipe.XDel(ctx, q.opts.Stream, id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Failed to acknowledge job %s: %v", id, err)
+	}
+}
+
+// backoff doubles the wait with every attempt, starting at BaseBackoff.
+func (q *Queue) backoff(attempt int) time.Duration {
+	wait := q.opts.BaseBackoff
+	for i := 1; i < attempt && wait < q.opts.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, q.opts.MaxBackoff)
+}
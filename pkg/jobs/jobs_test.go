@@ -0,0 +1,237 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+var greet = Type[greeting]{Name: "greet"}
+
+// newQueue returns a queue on a stream of its own in the Redis at REDIS_ADDR,
+// skipping the test when none is configured.
+func newQueue(t *testing.T, opts Options) (*Queue, *redis.Client) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR is not set")
+	}
+	rd := redis.NewClient(&redis.Options{Addr: addr, Password: os.Getenv("REDIS_PASS")})
+	if err := rd.Ping(context.Background()).Err(); err != nil {
+		t.Fatalf("ping %s: %v", addr, err)
+	}
+
+	opts.Stream = "jobs-test-" + uuid.New().String()
+	q := NewQueue(rd, opts)
+	t.Cleanup(func() {
+		rd.Del(context.Background(), q.opts.Stream, q.delayedKey(), q.DeadLetterStream())
+		rd.Close()
+	})
+	return q, rd
+}
+
+// run runs q until the test ends.
+func run(t *testing.T, q *Queue) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- q.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("Run: %v", err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Error("Run did not return after cancellation")
+		}
+	})
+}
+
+// eventually polls cond until it holds or the deadline passes.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// settled reports whether nothing is left on the stream or pending.
+func settled(t *testing.T, q *Queue, rd *redis.Client) bool {
+	ctx := context.Background()
+	n, err := rd.XLen(ctx, q.opts.Stream).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending, err := rd.XPending(ctx, q.opts.Stream, q.opts.Group).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n == 0 && pending.Count == 0
+}
+
+func deadLetters(t *testing.T, q *Queue, rd *redis.Client) []Job {
+	msgs, err := rd.XRange(context.Background(), q.DeadLetterStream(), "-", "+").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jobs []Job
+	for _, msg := range msgs {
+		var job Job
+		if err := json.Unmarshal([]byte(msg.Values["job"].(string)), &job); err != nil {
+			t.Fatal(err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+func TestQueueAcknowledges(t *testing.T) {
+	q, rd := newQueue(t, Options{})
+	got := make(chan greeting, 1)
+	Handle(q, greet, func(ctx context.Context, g greeting) error {
+		got <- g
+		return nil
+	})
+	run(t, q)
+
+	if err := greet.Enqueue(context.Background(), q, greeting{Name: "Ada"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case g := <-got:
+		if g.Name != "Ada" {
+			t.Errorf("payload = %+v", g)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("job was not handled")
+	}
+	eventually(t, "the job to be acknowledged", func() bool { return settled(t, q, rd) })
+}
+
+func TestQueueRetriesWithBackoff(t *testing.T) {
+	q, rd := newQueue(t, Options{BaseBackoff: 300 * time.Millisecond})
+	var attempts atomic.Int32
+	var failedAt, retriedAt atomic.Int64
+	Handle(q, greet, func(ctx context.Context, g greeting) error {
+		if attempts.Add(1) == 1 {
+			failedAt.Store(time.Now().UnixNano())
+			return errors.New("smtp is down")
+		}
+		retriedAt.Store(time.Now().UnixNano())
+		return nil
+	})
+	run(t, q)
+
+	if err := greet.Enqueue(context.Background(), q, greeting{Name: "Ada"}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the retry", func() bool { return attempts.Load() == 2 })
+	if wait := time.Duration(retriedAt.Load() - failedAt.Load()); wait < 300*time.Millisecond {
+		t.Errorf("retried after %s, before the backoff passed", wait)
+	}
+	eventually(t, "the retry to be acknowledged", func() bool { return settled(t, q, rd) })
+	if n := rd.ZCard(context.Background(), q.delayedKey()).Val(); n != 0 {
+		t.Errorf("%d jobs left delayed", n)
+	}
+	if dead := deadLetters(t, q, rd); len(dead) != 0 {
+		t.Errorf("dead letters = %+v", dead)
+	}
+}
+
+func TestQueueDeadLetters(t *testing.T) {
+	q, rd := newQueue(t, Options{MaxAttempts: 3, BaseBackoff: 10 * time.Millisecond})
+	var attempts atomic.Int32
+	Handle(q, greet, func(ctx context.Context, g greeting) error {
+		attempts.Add(1)
+		if g.Name == "permanent" {
+			return Permanent(errors.New("no such user"))
+		}
+		return errors.New("smtp is down")
+	})
+	run(t, q)
+
+	if err := greet.Enqueue(context.Background(), q, greeting{Name: "Ada"}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the job to be dead-lettered", func() bool { return len(deadLetters(t, q, rd)) == 1 })
+	dead := deadLetters(t, q, rd)[0]
+	if dead.Attempt != 3 || dead.LastError != "smtp is down" || dead.Type != greet.Name {
+		t.Errorf("dead letter = %+v", dead)
+	}
+	if n := attempts.Load(); n != 3 {
+		t.Errorf("handled %d times, want 3", n)
+	}
+
+	// A permanent error is not retried.
+	if err := greet.Enqueue(context.Background(), q, greeting{Name: "permanent"}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the permanent failure to be dead-lettered", func() bool { return len(deadLetters(t, q, rd)) == 2 })
+	if dead := deadLetters(t, q, rd)[1]; dead.Attempt != 1 {
+		t.Errorf("permanent failure attempted %d times", dead.Attempt)
+	}
+	eventually(t, "the stream to drain", func() bool { return settled(t, q, rd) })
+}
+
+func TestQueueClaimsJobsOfDeadWorkers(t *testing.T) {
+	q, rd := newQueue(t, Options{Consumer: "alive", ClaimIdle: 200 * time.Millisecond})
+	ctx := context.Background()
+	if err := q.ensureGroup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := greet.Enqueue(ctx, q, greeting{Name: "Ada"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another worker reads the job and dies before acknowledging it.
+	streams, err := rd.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    q.opts.Group,
+		Consumer: "dead",
+		Streams:  []string{q.opts.Stream, ">"},
+		Count:    1,
+	}).Result()
+	if err != nil || len(streams[0].Messages) != 1 {
+		t.Fatalf("read as the dead worker: %v %v", streams, err)
+	}
+
+	got := make(chan greeting, 1)
+	Handle(q, greet, func(ctx context.Context, g greeting) error {
+		got <- g
+		return nil
+	})
+	run(t, q)
+
+	select {
+	case g := <-got:
+		if g.Name != "Ada" {
+			t.Errorf("payload = %+v", g)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the dead worker's job was not claimed")
+	}
+	eventually(t, "the claimed job to be acknowledged", func() bool { return settled(t, q, rd) })
+}
+
+func TestBackoff(t *testing.T) {
+	q := NewQueue(nil, Options{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second})
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := q.backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
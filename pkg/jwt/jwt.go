@@ -11,14 +11,30 @@ import (
 	"github.com/golang-jwt/jwt"
 )
 
-func GenerateToken(payload sqlc.User) (string, error) {
+// AccessTokenExp is the lifetime of access tokens, read from JWT_EXP. Access
+// tokens are short-lived and renewed through the session's refresh token.
+func AccessTokenExp() time.Duration {
 	expStr := os.Getenv("JWT_EXP")
-	var exp time.Duration
 	exp, err := time.ParseDuration(expStr)
 	if expStr == "" || err != nil {
-		exp = time.Hour * 3
+		exp = time.Minute * 15
 	}
-	tokenJwtTemp := jwt.NewWithClaims(jwt.SigningMethodHS256, dto.NewUserClaims(payload.ID, exp))
+	return exp
+}
+
+// RefreshTokenExp is the idle lifetime of a session, read from
+// JWT_REFRESH_EXP. Every refresh extends the session by this amount.
+func RefreshTokenExp() time.Duration {
+	expStr := os.Getenv("JWT_REFRESH_EXP")
+	exp, err := time.ParseDuration(expStr)
+	if expStr == "" || err != nil {
+		exp = time.Hour * 24 * 30
+	}
+	return exp
+}
+
+func GenerateToken(payload sqlc.User, sessionID string, version int64) (string, error) {
+	tokenJwtTemp := jwt.NewWithClaims(jwt.SigningMethodHS256, dto.NewUserClaims(payload.ID, sessionID, payload.Role, version, AccessTokenExp()))
 	tokenJwt, err := tokenJwtTemp.SignedString([]byte(os.Getenv("JWT_SECRET_KEY")))
 	if err != nil {
 		return "", err
@@ -0,0 +1,87 @@
+package jwt
+
+import (
+	"context"
+	"courseworker/internal/dto"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Access tokens are revoked in three ways: a single token through a denylist
+// entry on its jti that lives until the token would expire anyway, all tokens
+// of a session through a denylist entry on the session ID that outlives every
+// token issued for it, and all tokens of a user at once by bumping the user's
+// token version. Tokens carry the version they were issued with and are
+// rejected once it is outdated.
+
+func denylistKey(jti string) string {
+	return "jwt-deny:" + jti
+}
+
+func sessionDenylistKey(sessionID string) string {
+	return "jwt-session-deny:" + sessionID
+}
+
+func tokenVersionKey(userID string) string {
+	return "token-version:" + userID
+}
+
+// DenyToken revokes a single access token until its expiry.
+func DenyToken(ctx context.Context, rd *redis.Client, claims *dto.UserClaims) error {
+	if claims.RegisteredClaims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+	return rd.Set(ctx, denylistKey(claims.RegisteredClaims.ID), 1, ttl).Err()
+}
+
+// DenySession revokes every access token issued for the session. Tokens live
+// no longer than AccessTokenExp, so neither does the entry.
+func DenySession(ctx context.Context, rd *redis.Client, sessionID string) error {
+	return rd.Set(ctx, sessionDenylistKey(sessionID), 1, AccessTokenExp()).Err()
+}
+
+// BumpTokenVersion revokes every access token issued to the user so far.
+func BumpTokenVersion(ctx context.Context, rd *redis.Client, userID string) error {
+	return rd.Incr(ctx, tokenVersionKey(userID)).Err()
+}
+
+// TokenVersion returns the version new tokens of the user are issued with.
+func TokenVersion(ctx context.Context, rd *redis.Client, userID string) (int64, error) {
+	version, err := rd.Get(ctx, tokenVersionKey(userID)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return version, err
+}
+
+// IsRevoked reports whether the token or its session was denylisted or the
+// token belongs to an outdated token version.
+func IsRevoked(ctx context.Context, rd *redis.Client, claims *dto.UserClaims) (bool, error) {
+	keys := []string{tokenVersionKey(claims.ID), denylistKey(claims.RegisteredClaims.ID)}
+	if claims.SessionID != "" {
+		keys = append(keys, sessionDenylistKey(claims.SessionID))
+	}
+	values, err := rd.MGet(ctx, keys...).Result()
+	if err != nil {
+		return false, err
+	}
+	for _, v := range values[1:] {
+		if v != nil {
+			return true, nil
+		}
+	}
+	if v, ok := values[0].(string); ok {
+		version, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return false, err
+		}
+		return claims.Version < version, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,126 @@
+package mailer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+const DefaultLocale = "en"
+
+// catalog holds the subject of every template per locale. Subjects are text
+// templates executed with the email's data; "duration" renders a
+// time.Duration in the locale's words. dueIn phrases a task's approaching
+// deadline outside of emails, from the task title and the formatted duration.
+var catalog = map[string]struct {
+	units    map[string][2]string // singular and plural of day, hour and minute
+	dueIn    string
+	subjects map[string]string
+}{
+	"en": {
+		units: map[string][2]string{"day": {"day", "days"}, "hour": {"hour", "hours"}, "minute": {"minute", "minutes"}},
+		dueIn: "%s is due in %s",
+		subjects: map[string]string{
+			AccountConfirm: "Email Confirmation",
+			Digest:         "Your {{.Frequency}} digest: {{.OverdueCount}} overdue, {{.DueTodayCount}} due today, {{.DueThisWeekCount}} this week",
+			EmailChange:    "Confirm Your New Email",
+			PasswordReset:  "Password Reset",
+			Reminder:       "Reminder: {{.TaskTitle}} is due in {{duration .Lead}}",
+		},
+	},
+	"id": {
+		units: map[string][2]string{"day": {"hari", "hari"}, "hour": {"jam", "jam"}, "minute": {"menit", "menit"}},
+		dueIn: "%s jatuh tempo dalam %s",
+		subjects: map[string]string{
+			AccountConfirm: "Konfirmasi Email",
+			Digest:         "Ringkasan tugas: {{.OverdueCount}} terlambat, {{.DueTodayCount}} jatuh tempo hari ini, {{.DueThisWeekCount}} minggu ini",
+			EmailChange:    "Konfirmasi Email Baru Anda",
+			PasswordReset:  "Atur Ulang Kata Sandi",
+			Reminder:       "Pengingat: {{.TaskTitle}} jatuh tempo dalam {{duration .Lead}}",
+		},
+	},
+}
+
+type locale struct {
+	subjects map[string]*texttemplate.Template
+}
+
+var locales = parseLocales()
+
+// parseLocales panics when a locale misses the subject of a template, so a
+// new template cannot ship without its translations.
+func parseLocales() map[string]locale {
+	parsed := map[string]locale{}
+	for name, c := range catalog {
+		units := c.units
+		fm := texttemplate.FuncMap{
+			"duration": func(d time.Duration) string { return formatDuration(d, units) },
+		}
+		l := locale{subjects: map[string]*texttemplate.Template{}}
+		for tmpl := range templates {
+			subject, ok := c.subjects[tmpl]
+			if !ok {
+				panic("mailer: locale " + name + " has no subject for " + tmpl)
+			}
+			l.subjects[tmpl] = texttemplate.Must(texttemplate.New(tmpl).Funcs(fm).Parse(subject))
+		}
+		parsed[name] = l
+	}
+	return parsed
+}
+
+// MatchLocale picks the first supported language of an Accept-Language
+// header, falling back to DefaultLocale.
+func MatchLocale(acceptLanguage string) string {
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ := strings.Cut(part, ";")
+		lang, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+		lang = strings.ToLower(lang)
+		if _, ok := catalog[lang]; ok {
+			return lang
+		}
+	}
+	return DefaultLocale
+}
+
+// FormatDuration renders d the way the "duration" subject function of the
+// locale does, falling back to DefaultLocale.
+func FormatDuration(d time.Duration, locale string) string {
+	c, ok := catalog[locale]
+	if !ok {
+		c = catalog[DefaultLocale]
+	}
+	return formatDuration(d, c.units)
+}
+
+// DueIn says that the task is due in d in the locale's words, falling back to
+// DefaultLocale.
+func DueIn(taskTitle string, d time.Duration, locale string) string {
+	c, ok := catalog[locale]
+	if !ok {
+		c = catalog[DefaultLocale]
+	}
+	return fmt.Sprintf(c.dueIn, taskTitle, formatDuration(d, c.units))
+}
+
+// formatDuration renders a duration in its largest whole unit, e.g. 1440
+// minutes as "1 day" and 90 minutes as "2 hours".
+func formatDuration(d time.Duration, units map[string][2]string) string {
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return "1 " + units[unit][0]
+		}
+		return strconv.Itoa(n) + " " + units[unit][1]
+	}
+	day := 24 * time.Hour
+	switch {
+	case d >= day:
+		return plural(int(d.Round(day)/day), "day")
+	case d >= time.Hour:
+		return plural(int(d.Round(time.Hour)/time.Hour), "hour")
+	default:
+		return plural(max(int(d.Round(time.Minute)/time.Minute), 1), "minute")
+	}
+}
@@ -0,0 +1,59 @@
+// Package mailer renders and delivers the application's emails.
+//
+// Emails are built from embedded templates that share one HTML and one text
+// layout, with subjects looked up per locale. Rendered messages are plain
+// values so they can be queued and handed to any Mailer later.
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/gomail.v2"
+)
+
+// Message is a rendered email ready for delivery. Text is optional; when set
+// it is sent as the plain-text alternative of HTML.
+type Message struct {
+	To      string            `json:"to"`
+	Subject string            `json:"subject"`
+	HTML    string            `json:"html"`
+	Text    string            `json:"text,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Mailer delivers rendered messages.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// From is the sender put on every message.
+type From struct {
+	Name  string
+	Email string
+}
+
+func (f From) String() string {
+	if f.Name == "" {
+		return f.Email
+	}
+	return fmt.Sprintf("%s <%s>", f.Name, f.Email)
+}
+
+// build turns a message into its MIME form.
+func build(from From, msg Message) *gomail.Message {
+	m := gomail.NewMessage()
+	m.SetHeader("From", from.String())
+	m.SetHeader("To", msg.To)
+	m.SetHeader("Subject", msg.Subject)
+	for k, v := range msg.Headers {
+		m.SetHeader(k, v)
+	}
+	if msg.Text != "" {
+		m.SetBody("text/plain", msg.Text)
+		m.AddAlternative("text/html", msg.HTML)
+	} else {
+		m.SetBody("text/html", msg.HTML)
+	}
+	return m
+}
@@ -0,0 +1,74 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Outbox keeps sent messages instead of delivering them, for tests and local
+// development. With a directory set every message is also written there as an
+// .eml file that mail clients can open.
+type Outbox struct {
+	dir  string
+	from From
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewOutbox(dir string, from From) (*Outbox, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &Outbox{dir: dir, from: from}, nil
+}
+
+func (o *Outbox) Send(ctx context.Context, msg Message) error {
+	o.mu.Lock()
+	o.messages = append(o.messages, msg)
+	n := len(o.messages)
+	o.mu.Unlock()
+
+	if o.dir == "" {
+		return nil
+	}
+	name := fmt.Sprintf("%s-%03d-%s.eml", time.Now().UTC().Format("20060102T150405"), n, sanitizeFileName(msg.To))
+	f, err := os.Create(filepath.Join(o.dir, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = build(o.from, msg).WriteTo(f)
+	return err
+}
+
+// Messages returns the messages sent so far, oldest first.
+func (o *Outbox) Messages() []Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]Message(nil), o.messages...)
+}
+
+// Reset forgets the sent messages. Files already written are kept.
+func (o *Outbox) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = nil
+}
+
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,68 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutbox(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	o, err := NewOutbox(dir, From{Name: "Courseworker", Email: "noreply@example.com"})
+	if err != nil {
+		t.Fatalf("NewOutbox: %v", err)
+	}
+
+	ctx := context.Background()
+	for _, to := range []string{"ada@example.com", "grace@example.com"} {
+		if err := o.Send(ctx, Message{To: to, Subject: "Hello", Text: "Hi", HTML: "<p>Hi</p>"}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	msgs := o.Messages()
+	if len(msgs) != 2 || msgs[0].To != "ada@example.com" || msgs[1].To != "grace@example.com" {
+		t.Fatalf("Messages = %+v", msgs)
+	}
+	msgs[0].To = "changed"
+	if o.Messages()[0].To != "ada@example.com" {
+		t.Error("Messages returned the outbox's own slice")
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil || len(files) != 2 {
+		t.Fatalf("written files = %v, %v", files, err)
+	}
+	raw, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"From: Courseworker <noreply@example.com>", "To: ada@example.com", "Subject: Hello"} {
+		if !strings.Contains(string(raw), want) {
+			t.Errorf("%s misses %q:\n%s", files[0], want, raw)
+		}
+	}
+
+	o.Reset()
+	if n := len(o.Messages()); n != 0 {
+		t.Errorf("%d messages after Reset", n)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.eml")); len(files) != 2 {
+		t.Errorf("Reset removed written files: %v", files)
+	}
+}
+
+func TestOutboxWithoutDir(t *testing.T) {
+	o, err := NewOutbox("", From{Email: "noreply@example.com"})
+	if err != nil {
+		t.Fatalf("NewOutbox: %v", err)
+	}
+	if err := o.Send(context.Background(), Message{To: "ada@example.com", Subject: "Hello"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if n := len(o.Messages()); n != 1 {
+		t.Errorf("%d messages, want 1", n)
+	}
+}
@@ -0,0 +1,156 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	From     From
+	// Timeout bounds a whole delivery when the context has no deadline, 30s
+	// by default.
+	Timeout time.Duration
+}
+
+// SMTP sends messages through an SMTP server, opening one connection per
+// message. A delivery is cut off at the context's deadline or cancellation,
+// so a stalled server cannot hold a job past its timeout.
+type SMTP struct {
+	cfg SMTPConfig
+}
+
+func NewSMTP(cfg SMTPConfig) *SMTP {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &SMTP{cfg}
+}
+
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	// Cancelling the context unblocks whatever read or write is in flight.
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	err = s.deliver(conn, msg)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// deliver speaks SMTP over conn. Port 465 is implicit TLS, any other port
+// upgrades with STARTTLS when the server offers it.
+func (s *SMTP) deliver(conn net.Conn, msg Message) error {
+	tlsConfig := &tls.Config{ServerName: s.cfg.Host}
+	if s.cfg.Port == 465 {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok && s.cfg.Port != 465 {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if s.cfg.User != "" {
+		if ok, mechanisms := c.Extension("AUTH"); ok {
+			if err := c.Auth(s.auth(mechanisms)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := c.Mail(s.cfg.From.Email); err != nil {
+		return err
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := build(s.cfg.From, msg).WriteTo(w); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// auth picks the mechanism the way the server prefers it: CRAM-MD5, then
+// LOGIN for servers that do not offer PLAIN, then PLAIN.
+func (s *SMTP) auth(mechanisms string) smtp.Auth {
+	switch {
+	case strings.Contains(mechanisms, "CRAM-MD5"):
+		return smtp.CRAMMD5Auth(s.cfg.User, s.cfg.Password)
+	case strings.Contains(mechanisms, "LOGIN") && !strings.Contains(mechanisms, "PLAIN"):
+		return &loginAuth{s.cfg.User, s.cfg.Password, s.cfg.Host}
+	default:
+		return smtp.PlainAuth("", s.cfg.User, s.cfg.Password, s.cfg.Host)
+	}
+}
+
+// loginAuth is the LOGIN mechanism, which net/smtp does not implement. Like
+// PlainAuth it refuses to send the password unencrypted to another host.
+type loginAuth struct {
+	username, password, host string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && server.Name != "localhost" && server.Name != "127.0.0.1" && server.Name != "::1" {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch string(fromServer) {
+	case "Username:":
+		return []byte(a.username), nil
+	case "Password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected server challenge %q", fromServer)
+	}
+}
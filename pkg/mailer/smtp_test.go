@@ -0,0 +1,133 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpServer accepts connections on a local port and hands each to serve.
+func smtpServer(t *testing.T, serve func(conn net.Conn)) SMTPConfig {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				serve(conn)
+			}()
+		}
+	}()
+
+	addr := l.Addr().(*net.TCPAddr)
+	return SMTPConfig{
+		Host: "127.0.0.1",
+		Port: addr.Port,
+		From: From{Name: "Courseworker", Email: "noreply@example.com"},
+	}
+}
+
+func TestSMTPSend(t *testing.T) {
+	received := make(chan string, 1)
+	cfg := smtpServer(t, func(conn net.Conn) {
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ready")
+		var transcript strings.Builder
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"):
+				transcript.WriteString(strings.TrimSpace(line) + "\n")
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					transcript.WriteString(line)
+				}
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				received <- transcript.String()
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	})
+
+	err := NewSMTP(cfg).Send(context.Background(), Message{
+		To:      "ada@example.com",
+		Subject: "Hello",
+		Text:    "Hi Ada",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	transcript := <-received
+	for _, want := range []string{"MAIL FROM:<noreply@example.com>", "RCPT TO:<ada@example.com>", "Subject: Hello", "Hi Ada"} {
+		if !strings.Contains(transcript, want) {
+			t.Errorf("transcript misses %q:\n%s", want, transcript)
+		}
+	}
+}
+
+func TestSMTPSendTimesOut(t *testing.T) {
+	// The server accepts the connection but never greets.
+	hold := make(chan struct{})
+	t.Cleanup(func() { close(hold) })
+	cfg := smtpServer(t, func(conn net.Conn) { <-hold })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := NewSMTP(cfg).Send(ctx, Message{To: "ada@example.com", Subject: "Hello", Text: "Hi"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Send = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Send took %s", elapsed)
+	}
+
+	// Without a deadline on the context the configured timeout applies.
+	cfg.Timeout = 200 * time.Millisecond
+	if err := NewSMTP(cfg).Send(context.Background(), Message{To: "ada@example.com"}); err == nil {
+		t.Error("Send to a silent server succeeded")
+	}
+}
+
+func TestSMTPSendCancelled(t *testing.T) {
+	hold := make(chan struct{})
+	t.Cleanup(func() { close(hold) })
+	cfg := smtpServer(t, func(conn net.Conn) { <-hold })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	if err := NewSMTP(cfg).Send(ctx, Message{To: "ada@example.com"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Send = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,139 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Templates of the emails the application sends. Each has an HTML and a text
+// version under templates/ and a subject in every locale.
+const (
+	AccountConfirm = "account_confirm"
+	Digest         = "digest"
+	EmailChange    = "email_change"
+	PasswordReset  = "password_reset"
+	Reminder       = "reminder"
+)
+
+// LinkData is the data of the emails that carry a single action link.
+type LinkData struct {
+	Link          string
+	ExpiryMinutes int
+}
+
+// ReminderData is the data of the Reminder email. Lead is the time left until
+// the deadline.
+type ReminderData struct {
+	UserName   string
+	TaskTitle  string
+	CourseName string
+	Deadline   time.Time
+	Lead       time.Duration
+}
+
+// Email describes a message to render.
+type Email struct {
+	To       string
+	Template string
+	// Locale picks the subject language, DefaultLocale when empty or unknown.
+	Locale string
+	Data   interface{}
+	// UnsubscribeURL adds an unsubscribe link to the footer and the
+	// List-Unsubscribe headers that let mail clients offer one-click
+	// unsubscribe.
+	UnsubscribeURL string
+}
+
+// page is what the layouts are executed with; the email's own content gets
+// Data.
+type page struct {
+	Data           interface{}
+	UnsubscribeURL string
+}
+
+//go:embed templates
+var templateFS embed.FS
+
+var funcs = map[string]interface{}{
+	"dict": func(pairs ...interface{}) (map[string]interface{}, error) {
+		if len(pairs)%2 != 0 {
+			return nil, errors.New("dict needs key and value pairs")
+		}
+		m := make(map[string]interface{}, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			key, ok := pairs[i].(string)
+			if !ok {
+				return nil, errors.New("dict keys must be strings")
+			}
+			m[key] = pairs[i+1]
+		}
+		return m, nil
+	},
+}
+
+type emailTemplate struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+var templates = parseTemplates()
+
+// parseTemplates parses every email into its own copy of the layouts, whose
+// "content" block the email defines.
+func parseTemplates() map[string]emailTemplate {
+	layoutHTML := htmltemplate.Must(htmltemplate.New("layout.html.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/layout.html.tmpl"))
+	layoutText := texttemplate.Must(texttemplate.New("layout.txt.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/layout.txt.tmpl"))
+
+	parsed := map[string]emailTemplate{}
+	for _, name := range []string{AccountConfirm, Digest, EmailChange, PasswordReset, Reminder} {
+		parsed[name] = emailTemplate{
+			html: htmltemplate.Must(htmltemplate.Must(layoutHTML.Clone()).ParseFS(templateFS, "templates/"+name+".html.tmpl")),
+			text: texttemplate.Must(texttemplate.Must(layoutText.Clone()).ParseFS(templateFS, "templates/"+name+".txt.tmpl")),
+		}
+	}
+	return parsed
+}
+
+// Render builds the message for an email.
+func Render(e Email) (Message, error) {
+	t, ok := templates[e.Template]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %q", e.Template)
+	}
+	l, ok := locales[e.Locale]
+	if !ok {
+		l = locales[DefaultLocale]
+	}
+
+	var subject, html, text bytes.Buffer
+	if err := l.subjects[e.Template].Execute(&subject, e.Data); err != nil {
+		return Message{}, fmt.Errorf("render %s subject: %w", e.Template, err)
+	}
+	p := page{Data: e.Data, UnsubscribeURL: e.UnsubscribeURL}
+	if err := t.html.ExecuteTemplate(&html, "layout.html.tmpl", p); err != nil {
+		return Message{}, fmt.Errorf("render %s: %w", e.Template, err)
+	}
+	if err := t.text.ExecuteTemplate(&text, "layout.txt.tmpl", p); err != nil {
+		return Message{}, fmt.Errorf("render %s: %w", e.Template, err)
+	}
+
+	msg := Message{
+		To:      e.To,
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    html.String(),
+		Text:    text.String(),
+	}
+	if e.UnsubscribeURL != "" {
+		msg.Headers = map[string]string{
+			"List-Unsubscribe":      "<" + e.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}
+	}
+	return msg, nil
+}
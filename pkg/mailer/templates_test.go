@@ -0,0 +1,134 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func reminder(locale string) Email {
+	return Email{
+		To:       "ada@example.com",
+		Template: Reminder,
+		Locale:   locale,
+		Data: ReminderData{
+			UserName:   "Ada",
+			TaskTitle:  "Essay <draft>",
+			CourseName: "History",
+			Deadline:   time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+			Lead:       26 * time.Hour,
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	msg, err := Render(reminder("en"))
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if msg.To != "ada@example.com" {
+		t.Errorf("To = %q", msg.To)
+	}
+	if want := "Reminder: Essay <draft> is due in 1 day"; msg.Subject != want {
+		t.Errorf("Subject = %q, want %q", msg.Subject, want)
+	}
+	if !strings.Contains(msg.HTML, "<b>Essay &lt;draft&gt;</b>") {
+		t.Errorf("HTML does not escape the task title:\n%s", msg.HTML)
+	}
+	if !strings.Contains(msg.Text, "Essay <draft> (History) is due on Fri, 14 Mar 2025 09:00 UTC.") {
+		t.Errorf("Text = %q", msg.Text)
+	}
+	if msg.Headers != nil {
+		t.Errorf("Headers = %v, want none without an unsubscribe URL", msg.Headers)
+	}
+}
+
+func TestRenderLocale(t *testing.T) {
+	for locale, want := range map[string]string{
+		"id":    "Pengingat: Essay <draft> jatuh tempo dalam 1 hari",
+		"":      "Reminder: Essay <draft> is due in 1 day",
+		"fr-FR": "Reminder: Essay <draft> is due in 1 day",
+	} {
+		msg, err := Render(reminder(locale))
+		if err != nil {
+			t.Fatalf("Render %q: %v", locale, err)
+		}
+		if msg.Subject != want {
+			t.Errorf("Subject in %q = %q, want %q", locale, msg.Subject, want)
+		}
+	}
+}
+
+func TestRenderUnsubscribe(t *testing.T) {
+	e := reminder("en")
+	e.UnsubscribeURL = "https://example.com/digest/unsubscribe?uid=1&sig=ab"
+	msg, err := Render(e)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if got := msg.Headers["List-Unsubscribe"]; got != "<"+e.UnsubscribeURL+">" {
+		t.Errorf("List-Unsubscribe = %q", got)
+	}
+	if got := msg.Headers["List-Unsubscribe-Post"]; got != "List-Unsubscribe=One-Click" {
+		t.Errorf("List-Unsubscribe-Post = %q", got)
+	}
+	if !strings.Contains(msg.HTML, `href="https://example.com/digest/unsubscribe?uid=1&amp;sig=ab"`) {
+		t.Errorf("HTML has no unsubscribe link:\n%s", msg.HTML)
+	}
+	if !strings.Contains(msg.Text, "Unsubscribe: "+e.UnsubscribeURL) {
+		t.Errorf("Text has no unsubscribe link:\n%s", msg.Text)
+	}
+}
+
+func TestRenderUnknownTemplate(t *testing.T) {
+	if _, err := Render(Email{To: "ada@example.com", Template: "missing"}); err == nil {
+		t.Error("Render of an unknown template succeeded")
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	for header, want := range map[string]string{
+		"":                        DefaultLocale,
+		"id":                      "id",
+		"id-ID,id;q=0.9,en;q=0.8": "id",
+		"fr-FR,fr;q=0.9,ID;q=0.5": "id",
+		"fr-FR, de;q=0.9":         DefaultLocale,
+		"en-GB,en;q=0.9,id;q=0.8": "en",
+	} {
+		if got := MatchLocale(header); got != want {
+			t.Errorf("MatchLocale(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for _, tc := range []struct {
+		d      time.Duration
+		locale string
+		want   string
+	}{
+		{24 * time.Hour, "en", "1 day"},
+		{60 * time.Hour, "en", "3 days"},
+		{90 * time.Minute, "en", "2 hours"},
+		{time.Hour, "en", "1 hour"},
+		{10 * time.Second, "en", "1 minute"},
+		{45 * time.Minute, "id", "45 menit"},
+		{48 * time.Hour, "unknown", "2 days"},
+	} {
+		if got := FormatDuration(tc.d, tc.locale); got != tc.want {
+			t.Errorf("FormatDuration(%s, %q) = %q, want %q", tc.d, tc.locale, got, tc.want)
+		}
+	}
+}
+
+func TestDueIn(t *testing.T) {
+	for locale, want := range map[string]string{
+		"en":      "Essay is due in 2 hours",
+		"id":      "Essay jatuh tempo dalam 2 jam",
+		"unknown": "Essay is due in 2 hours",
+	} {
+		if got := DueIn("Essay", 2*time.Hour, locale); got != want {
+			t.Errorf("DueIn in %q = %q, want %q", locale, got, want)
+		}
+	}
+}
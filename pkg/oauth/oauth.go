@@ -0,0 +1,168 @@
+// Package oauth runs the OpenID Connect authorization code flow with PKCE
+// against the configured login providers. Provider endpoints and signing keys
+// are discovered from the issuer, and the returned ID token is verified
+// against the issuer's JWKS before its claims are trusted.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// ErrUnknownProvider is returned for provider names that are not configured.
+var ErrUnknownProvider = errors.New("unknown login provider")
+
+// Profile is the subset of the standard OpenID Connect claims used to find or
+// create the local account.
+type Profile struct {
+	Subject       string   `json:"sub"`
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	Name          string   `json:"name"`
+	Picture       string   `json:"picture"`
+}
+
+// Config describes one OpenID Connect provider.
+type Config struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// TrustEmail marks the provider as authoritative for the email addresses
+	// it verifies, so its logins may be linked to an existing account with
+	// the same address.
+	TrustEmail bool
+}
+
+// Provider is a configured provider whose discovery document has been loaded.
+type Provider struct {
+	Name     string
+	config   *oauth2.Config
+	oidc     *oidc.Provider
+	verifier *oidc.IDTokenVerifier
+}
+
+// discoveryTimeout bounds the request for the issuer's discovery document.
+const discoveryTimeout = 10 * time.Second
+
+// NewProvider fetches the issuer's discovery document and prepares the flow.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+
+	p, err := oidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("discover %s: %w", cfg.Issuer, err)
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{oidc.ScopeOpenID, "email", "profile"}
+	}
+	return &Provider{
+		Name: cfg.Name,
+		config: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       scopes,
+			Endpoint:     p.Endpoint(),
+		},
+		oidc:     p,
+		verifier: p.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+	}, nil
+}
+
+// AuthCodeURL returns the consent page URL. The verifier is kept by the caller
+// and handed back to Exchange; only its S256 challenge leaves the server.
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	return p.config.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange trades the authorization code for tokens, verifies the ID token
+// and returns the profile it describes. Claims missing from the ID token are
+// filled in from the userinfo endpoint.
+func (p *Provider) Exchange(ctx context.Context, code, nonce, verifier string) (*Profile, error) {
+	token, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("exchange code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("token response has no id_token")
+	}
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("verify id_token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce does not match")
+	}
+
+	var profile Profile
+	if err := idToken.Claims(&profile); err != nil {
+		return nil, fmt.Errorf("decode id_token claims: %w", err)
+	}
+
+	if profile.Email == "" {
+		info, err := p.oidc.UserInfo(ctx, oauth2.StaticTokenSource(token))
+		if err != nil {
+			return nil, fmt.Errorf("fetch userinfo: %w", err)
+		}
+		var extra Profile
+		if err := info.Claims(&extra); err != nil {
+			return nil, fmt.Errorf("decode userinfo: %w", err)
+		}
+		// The userinfo subject must match the verified token's.
+		if extra.Subject != idToken.Subject {
+			return nil, errors.New("userinfo subject does not match id_token")
+		}
+		profile.Email, profile.EmailVerified = extra.Email, extra.EmailVerified
+		if profile.Name == "" {
+			profile.Name = extra.Name
+		}
+		if profile.Picture == "" {
+			profile.Picture = extra.Picture
+		}
+	}
+
+	profile.Subject = idToken.Subject
+	if profile.Email == "" {
+		return nil, errors.New("provider did not return an email address")
+	}
+	return &profile, nil
+}
+
+// NewVerifier returns a fresh PKCE code verifier.
+func NewVerifier() string {
+	return oauth2.GenerateVerifier()
+}
+
+// flexBool accepts both JSON booleans and the "true"/"false" strings some
+// providers send for email_verified.
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case bool:
+		*b = flexBool(v)
+	case string:
+		*b = flexBool(v == "true")
+	default:
+		*b = false
+	}
+	return nil
+}
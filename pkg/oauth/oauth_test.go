@@ -0,0 +1,361 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// idp is a minimal OpenID Connect provider: discovery, JWKS, a token endpoint
+// checking the PKCE verifier and a userinfo endpoint.
+type idp struct {
+	t   *testing.T
+	srv *httptest.Server
+	key *rsa.PrivateKey
+
+	// discovery, when set, holds the discovery document back until closed.
+	discovery chan struct{}
+
+	mu       sync.Mutex
+	claims   map[string]interface{} // extra ID token claims
+	userinfo map[string]interface{}
+	codes    map[string]authRequest
+}
+
+type authRequest struct {
+	challenge string
+	nonce     string
+}
+
+func newIdP(t *testing.T) *idp {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &idp{t: t, key: key, codes: map[string]authRequest{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.serveDiscovery)
+	mux.HandleFunc("/keys", p.serveKeys)
+	mux.HandleFunc("/token", p.serveToken)
+	mux.HandleFunc("/userinfo", p.serveUserinfo)
+	p.srv = httptest.NewServer(mux)
+	t.Cleanup(p.srv.Close)
+	return p
+}
+
+func (p *idp) config() Config {
+	return Config{
+		Name:         "test",
+		Issuer:       p.srv.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/callback",
+	}
+}
+
+// authorize plays the consent page: it records the request behind an auth
+// code URL and returns the code the IdP redirects back with.
+func (p *idp) authorize(authURL string) string {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("client_id") != "client" || q.Get("response_type") != "code" {
+		p.t.Fatalf("unexpected auth request %s", authURL)
+	}
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		p.t.Fatalf("auth request without S256 challenge: %s", authURL)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	code := "code-" + q.Get("state")
+	p.codes[code] = authRequest{challenge: q.Get("code_challenge"), nonce: q.Get("nonce")}
+	return code
+}
+
+func (p *idp) serveDiscovery(w http.ResponseWriter, r *http.Request) {
+	if p.discovery != nil {
+		<-p.discovery
+	}
+	writeJSON(w, map[string]interface{}{
+		"issuer":                                p.srv.URL,
+		"authorization_endpoint":                p.srv.URL + "/authorize",
+		"token_endpoint":                        p.srv.URL + "/token",
+		"jwks_uri":                              p.srv.URL + "/keys",
+		"userinfo_endpoint":                     p.srv.URL + "/userinfo",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (p *idp) serveKeys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (p *idp) serveToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p.mu.Lock()
+	req, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	claims := map[string]interface{}{}
+	for k, v := range p.claims {
+		claims[k] = v
+	}
+	p.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != req.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	base := map[string]interface{}{
+		"iss":   p.srv.URL,
+		"aud":   "client",
+		"sub":   "subject-1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": req.nonce,
+	}
+	for k, v := range base {
+		if _, ok := claims[k]; !ok {
+			claims[k] = v
+		}
+	}
+	writeJSON(w, map[string]interface{}{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     p.sign(claims),
+	})
+}
+
+func (p *idp) serveUserinfo(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer access-token" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	writeJSON(w, p.userinfo)
+}
+
+// sign returns an RS256 JWT carrying claims.
+func (p *idp) sign(claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test", "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	signing := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	sum := sha256.Sum256([]byte(signing))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, sum[:])
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return signing + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// login runs the flow up to the callback and returns what Exchange makes of
+// it, with the nonce the callback checks against.
+func login(t *testing.T, p *idp, callbackNonce string) (*Profile, error) {
+	t.Helper()
+	ctx := context.Background()
+	provider, err := NewRegistry([]Config{p.config()}).Get(ctx, "test")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	verifier := NewVerifier()
+	code := p.authorize(provider.AuthCodeURL("state", "nonce-1", verifier))
+	return provider.Exchange(ctx, code, callbackNonce, verifier)
+}
+
+func TestExchange(t *testing.T) {
+	p := newIdP(t)
+	p.claims = map[string]interface{}{
+		"email":          "ada@example.com",
+		"email_verified": "true",
+		"name":           "Ada",
+		"picture":        "https://example.com/ada.png",
+	}
+
+	profile, err := login(t, p, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	want := Profile{
+		Subject:       "subject-1",
+		Email:         "ada@example.com",
+		EmailVerified: true,
+		Name:          "Ada",
+		Picture:       "https://example.com/ada.png",
+	}
+	if *profile != want {
+		t.Errorf("profile = %+v, want %+v", *profile, want)
+	}
+}
+
+func TestExchangeRejectsWrongVerifier(t *testing.T) {
+	p := newIdP(t)
+	p.claims = map[string]interface{}{"email": "ada@example.com"}
+
+	ctx := context.Background()
+	provider, err := NewProvider(ctx, p.config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := p.authorize(provider.AuthCodeURL("state", "nonce-1", NewVerifier()))
+	if _, err := provider.Exchange(ctx, code, "nonce-1", NewVerifier()); err == nil {
+		t.Error("Exchange with another verifier succeeded")
+	}
+}
+
+func TestExchangeRejectsNonceMismatch(t *testing.T) {
+	p := newIdP(t)
+	p.claims = map[string]interface{}{"email": "ada@example.com"}
+
+	if _, err := login(t, p, "other-nonce"); err == nil {
+		t.Error("Exchange with a mismatched nonce succeeded")
+	}
+}
+
+func TestExchangeRejectsForeignIDToken(t *testing.T) {
+	for name, claims := range map[string]map[string]interface{}{
+		"audience": {"aud": "other-client"},
+		"issuer":   {"iss": "https://evil.example.com"},
+		"expired":  {"exp": time.Now().Add(-time.Hour).Unix()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := newIdP(t)
+			p.claims = map[string]interface{}{"email": "ada@example.com"}
+			for k, v := range claims {
+				p.claims[k] = v
+			}
+			if _, err := login(t, p, "nonce-1"); err == nil {
+				t.Error("Exchange accepted the ID token")
+			}
+		})
+	}
+}
+
+func TestExchangeUserinfo(t *testing.T) {
+	p := newIdP(t)
+	p.claims = map[string]interface{}{"name": "Ada"}
+	p.userinfo = map[string]interface{}{
+		"sub":            "subject-1",
+		"email":          "ada@example.com",
+		"email_verified": true,
+		"name":           "Someone Else",
+	}
+
+	profile, err := login(t, p, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if profile.Email != "ada@example.com" || !bool(profile.EmailVerified) {
+		t.Errorf("email = %q verified %v, want it from userinfo", profile.Email, profile.EmailVerified)
+	}
+	if profile.Name != "Ada" {
+		t.Errorf("name = %q, want the ID token's", profile.Name)
+	}
+
+	p.userinfo["sub"] = "subject-2"
+	if _, err := login(t, p, "nonce-1"); err == nil {
+		t.Error("Exchange accepted userinfo of another subject")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	slow, fast := newIdP(t), newIdP(t)
+	slow.discovery = make(chan struct{})
+
+	slowCfg, fastCfg := slow.config(), fast.config()
+	slowCfg.Name, fastCfg.Name = "slow", "fast"
+	r := NewRegistry([]Config{slowCfg, fastCfg})
+
+	if names := r.Names(); strings.Join(names, ",") != "fast,slow" {
+		t.Errorf("Names = %v", names)
+	}
+	if _, err := r.Get(ctx, "missing"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("Get of an unknown provider = %v, want ErrUnknownProvider", err)
+	}
+
+	slowDone := make(chan error, 1)
+	go func() {
+		_, err := r.Get(ctx, "slow")
+		slowDone <- err
+	}()
+
+	// A provider whose discovery hangs must not hold up the others.
+	fastDone := make(chan error, 1)
+	go func() {
+		_, err := r.Get(ctx, "fast")
+		fastDone <- err
+	}()
+	select {
+	case err := <-fastDone:
+		if err != nil {
+			t.Fatalf("Get fast: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Get fast waited for the slow provider's discovery")
+	}
+
+	close(slow.discovery)
+	if err := <-slowDone; err != nil {
+		t.Fatalf("Get slow: %v", err)
+	}
+
+	first, _ := r.Get(ctx, "fast")
+	second, _ := r.Get(ctx, "fast")
+	if first != second {
+		t.Error("Get ran discovery again for a discovered provider")
+	}
+}
+
+func TestRegistryTrustsEmail(t *testing.T) {
+	r := NewRegistry([]Config{{Name: "trusted", TrustEmail: true}, {Name: "other"}})
+	for name, want := range map[string]bool{"trusted": true, "other": false, "missing": false} {
+		if got := r.TrustsEmail(name); got != want {
+			t.Errorf("TrustsEmail(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if (*Registry)(nil).TrustsEmail("trusted") {
+		t.Error("a nil registry trusts a provider")
+	}
+}
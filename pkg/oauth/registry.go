@@ -0,0 +1,80 @@
+package oauth
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Registry holds the configured providers. Discovery runs on first use rather
+// than at startup so the server comes up while an IdP is unreachable; a failed
+// discovery is retried on the next login.
+type Registry struct {
+	entries map[string]*registryEntry
+}
+
+// registryEntry guards the discovery of one provider, so a slow or
+// unreachable IdP only holds up logins through that provider.
+type registryEntry struct {
+	cfg Config
+
+	mu       sync.Mutex
+	provider *Provider
+}
+
+func NewRegistry(configs []Config) *Registry {
+	r := &Registry{entries: map[string]*registryEntry{}}
+	for _, cfg := range configs {
+		r.entries[cfg.Name] = &registryEntry{cfg: cfg}
+	}
+	return r
+}
+
+// Get returns the named provider, running discovery if needed. Concurrent
+// calls for a provider that is still being discovered wait for that discovery
+// instead of starting their own.
+func (r *Registry) Get(ctx context.Context, name string) (*Provider, error) {
+	if r == nil {
+		return nil, ErrUnknownProvider
+	}
+	e, ok := r.entries[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.provider != nil {
+		return e.provider, nil
+	}
+	p, err := NewProvider(ctx, e.cfg)
+	if err != nil {
+		return nil, err
+	}
+	e.provider = p
+	return p, nil
+}
+
+// TrustsEmail reports whether the named provider is configured as
+// authoritative for the email addresses it verifies.
+func (r *Registry) TrustsEmail(name string) bool {
+	if r == nil {
+		return false
+	}
+	e, ok := r.entries[name]
+	return ok && e.cfg.TrustEmail
+}
+
+// Names lists the configured provider names in alphabetical order.
+func (r *Registry) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
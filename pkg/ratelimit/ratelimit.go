@@ -0,0 +1,93 @@
+// Package ratelimit implements a sliding-window rate limiter on Redis. Every
+// allowed hit is recorded in a sorted set scored by its time, so a window
+// always covers exactly the last Window of traffic instead of resetting at
+// fixed boundaries.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "ratelimit:"
+
+// Rule allows Limit hits per Window for every key.
+type Rule struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// FromEnv returns the rule with Limit and Window taken from
+// RATE_LIMIT_<NAME> when it is set, in the form "<limit>/<window>",
+// e.g. "5/1m". Malformed values keep the default.
+func FromEnv(def Rule) Rule {
+	value := os.Getenv("RATE_LIMIT_" + strings.ToUpper(strings.ReplaceAll(def.Name, "-", "_")))
+	limitStr, windowStr, ok := strings.Cut(value, "/")
+	if !ok {
+		return def
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+	if err != nil || limit <= 0 {
+		return def
+	}
+	window, err := time.ParseDuration(strings.TrimSpace(windowStr))
+	if err != nil || window <= 0 {
+		return def
+	}
+	def.Limit, def.Window = limit, window
+	return def
+}
+
+// slidingWindow drops hits older than the window, records the new hit when
+// there is room and otherwise reports how long until the oldest hit leaves
+// the window. Running it as one script keeps concurrent hits from all slipping
+// through on the same count.
+var slidingWindow = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+if redis.call('ZCARD', key) < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	redis.call('PEXPIRE', key, window)
+	return 0
+end
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+return tonumber(oldest[2]) + window - now
+`)
+
+type Limiter struct {
+	rd *redis.Client
+}
+
+func New(rd *redis.Client) *Limiter {
+	return &Limiter{rd}
+}
+
+// Allow records a hit for key under the rule. When the limit is reached it
+// returns false and the time until the next hit would be allowed.
+func (l *Limiter) Allow(ctx context.Context, rule Rule, key string) (bool, time.Duration, error) {
+	now := time.Now().UnixMilli()
+	wait, err := slidingWindow.Run(ctx, l.rd,
+		[]string{fmt.Sprintf("%s%s:%s", keyPrefix, rule.Name, key)},
+		now, rule.Window.Milliseconds(), rule.Limit, uuid.New().String(),
+	).Int64()
+	if err != nil {
+		return false, 0, err
+	}
+	if wait > 0 {
+		return false, time.Duration(wait) * time.Millisecond, nil
+	}
+	return true, 0, nil
+}
@@ -0,0 +1,180 @@
+// Package recurrence implements the subset of RFC 5545 recurrence rules used
+// by recurring tasks: weekly repetition on given weekdays, bounded either by
+// an end date (UNTIL) or by a number of occurrences (COUNT).
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxOccurrences caps how many occurrences a single rule may expand to.
+const MaxOccurrences = 100
+
+const maxInterval = 52
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Rule is a parsed weekly recurrence rule.
+type Rule struct {
+	Interval int
+	Weekdays []time.Weekday
+	Count    int
+	Until    time.Time
+
+	// untilDate is set when UNTIL was given without a time of day, in which
+	// case the whole day counts, in the zone of the series start.
+	untilDate bool
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20250601" or
+// "FREQ=WEEKLY;COUNT=10". An optional "RRULE:" prefix is accepted.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, errors.New("recurrence rule is empty")
+	}
+
+	r := &Rule{Interval: 1}
+	var freq string
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed recurrence part %q", part)
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			freq = strings.ToUpper(val)
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 || n > maxInterval {
+				return nil, fmt.Errorf("INTERVAL must be a number between 1 and %d", maxInterval)
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, d := range strings.Split(strings.ToUpper(val), ",") {
+				wd, ok := weekdays[d]
+				if !ok {
+					return nil, fmt.Errorf("unknown weekday %q in BYDAY", d)
+				}
+				r.Weekdays = append(r.Weekdays, wd)
+			}
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 || n > MaxOccurrences {
+				return nil, fmt.Errorf("COUNT must be a number between 1 and %d", MaxOccurrences)
+			}
+			r.Count = n
+		case "UNTIL":
+			until, dateOnly, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			r.Until, r.untilDate = until, dateOnly
+		default:
+			return nil, fmt.Errorf("unsupported recurrence part %q", key)
+		}
+	}
+
+	if freq != "WEEKLY" {
+		return nil, errors.New("only FREQ=WEEKLY is supported")
+	}
+	if r.Count == 0 && r.Until.IsZero() {
+		return nil, errors.New("recurrence needs either COUNT or UNTIL")
+	}
+	if r.Count != 0 && !r.Until.IsZero() {
+		return nil, errors.New("COUNT and UNTIL cannot be combined")
+	}
+	return r, nil
+}
+
+func parseUntil(value string) (time.Time, bool, error) {
+	for _, layout := range []string{"20060102T150405Z", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, false, nil
+		}
+	}
+	for _, layout := range []string{"20060102", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("invalid UNTIL value %q", value)
+}
+
+// String returns the canonical form of the rule.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=WEEKLY"}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.Weekdays) > 0 {
+		days := make([]string, len(r.Weekdays))
+		for i, wd := range r.Weekdays {
+			days[i] = strings.ToUpper(wd.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		if r.untilDate {
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences expands the rule starting at start. Every occurrence keeps the
+// clock time of start; start itself is only included when its weekday is
+// part of the rule. Weeks start on Monday, as in RFC 5545.
+func (r *Rule) Occurrences(start time.Time) ([]time.Time, error) {
+	days := map[time.Weekday]bool{}
+	for _, wd := range r.Weekdays {
+		days[wd] = true
+	}
+	if len(days) == 0 {
+		days[start.Weekday()] = true
+	}
+
+	until := r.Until
+	if r.untilDate {
+		y, m, d := r.Until.Date()
+		until = time.Date(y, m, d, 23, 59, 59, 0, start.Location())
+	}
+
+	offset := (int(start.Weekday()) + 6) % 7
+	var result []time.Time
+	for i := 0; ; i++ {
+		day := start.AddDate(0, 0, i)
+		if !until.IsZero() && day.After(until) {
+			break
+		}
+		week := (i + offset) / 7
+		if week%r.Interval != 0 || !days[day.Weekday()] {
+			continue
+		}
+		if len(result) == MaxOccurrences {
+			return nil, fmt.Errorf("recurrence must not exceed %d occurrences", MaxOccurrences)
+		}
+		result = append(result, day)
+		if r.Count > 0 && len(result) == r.Count {
+			break
+		}
+	}
+	return result, nil
+}
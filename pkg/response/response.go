@@ -9,6 +9,8 @@ import (
 	"log/slog"
 	"net/http"
 	"reflect"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -23,6 +25,15 @@ func Success(c *gin.Context, httpCode int, msg string, data interface{}) {
 	})
 }
 
+func SuccessWithMeta(c *gin.Context, httpCode int, msg string, data interface{}, meta *dto.ResponseMeta) {
+	c.JSON(httpCode, dto.Response{
+		Status:  true,
+		Message: msg,
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
 type ServiceError struct {
 	RequestID string      `json:"request_id,omitempty"`
 	Kind      string      `json:"kind,omitempty"`
@@ -40,10 +51,18 @@ func getKindStatusCode(arg _error.Kind) int {
 	switch arg {
 	case _error.NotExist:
 		return http.StatusNotFound
+	case _error.Unauthorized:
+		return http.StatusUnauthorized
 	case _error.Forbidden:
 		return http.StatusForbidden
 	case _error.InvalidRequest:
 		return http.StatusBadRequest
+	case _error.Exist:
+		return http.StatusConflict
+	case _error.TooManyRequests:
+		return http.StatusTooManyRequests
+	case _error.TooLarge:
+		return http.StatusRequestEntityTooLarge
 	case _error.Database, _error.Internal:
 		return http.StatusInternalServerError
 	default:
@@ -81,6 +100,10 @@ func HttpError(c *gin.Context, err error) {
 
 		slog.With("data", logData).Error(string(problem.Title))
 
+		if problem.RetryAfter > 0 {
+			c.Header("Retry-After", retryAfterSeconds(time.Duration(problem.RetryAfter)))
+		}
+
 		if status == 500 {
 			c.JSON(status, &ResponseError{
 				Status:  false,
@@ -129,6 +152,12 @@ func HttpError(c *gin.Context, err error) {
 	})
 }
 
+// retryAfterSeconds formats a wait as whole seconds, rounded up so clients
+// never retry too early.
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10)
+}
+
 func getJSONFieldName(structType reflect.Type, fieldName string) string {
 	if structType.Kind() == reflect.Ptr {
 		structType = structType.Elem()
@@ -254,6 +283,12 @@ func validationReasonMessage(fieldErr validator.FieldError) string {
 		return "must be a valid email format"
 	case "url":
 		return "must be a valid URL format"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", fieldErr.Param())
+	case "min":
+		return fmt.Sprintf("must be at least %s", fieldErr.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s", fieldErr.Param())
 	default:
 		return fmt.Sprintf("failed validation for tag '%s'", fieldErr.Tag())
 	}
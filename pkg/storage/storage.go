@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotFound is returned when the requested key does not exist in the store.
+var ErrNotFound = errors.New("blob not found")
+
+// Object is an opened blob along with the metadata needed to serve it.
+type Object struct {
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+}
+
+// Blob is a key-addressed file store. Keys are slash separated paths such as
+// "tasks/<taskID>/image.png" so that everything belonging to one resource can
+// be removed with a single DeletePrefix call.
+type Blob interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (*Object, error)
+	Delete(ctx context.Context, key string) error
+	DeletePrefix(ctx context.Context, prefix string) error
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testBlob runs the behaviour every Blob implementation must share.
+func testBlob(t *testing.T, b Blob) {
+	ctx := context.Background()
+	body := []byte("hello blob")
+
+	if err := b.Put(ctx, "tasks/t1/image.png", bytes.NewReader(body), int64(len(body)), "image/png"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := b.Put(ctx, "tasks/t1/other.txt", strings.NewReader("x"), 1, "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	obj, err := b.Open(ctx, "tasks/t1/image.png")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	got, err := io.ReadAll(obj.Body)
+	obj.Body.Close()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !bytes.Equal(got, body) {
+		t.Errorf("body = %q, want %q", got, body)
+	}
+	if obj.Size != int64(len(body)) {
+		t.Errorf("size = %d, want %d", obj.Size, len(body))
+	}
+	if obj.ContentType != "image/png" {
+		t.Errorf("content type = %q, want image/png", obj.ContentType)
+	}
+
+	if _, err := b.SignedURL(ctx, "tasks/t1/image.png", time.Minute); err != nil {
+		t.Errorf("SignedURL: %v", err)
+	}
+
+	if err := b.Delete(ctx, "tasks/t1/image.png"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := b.Open(ctx, "tasks/t1/image.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after Delete = %v, want ErrNotFound", err)
+	}
+	if err := b.Delete(ctx, "tasks/t1/image.png"); err != nil {
+		t.Errorf("Delete of a missing key = %v, want nil", err)
+	}
+
+	if err := b.DeletePrefix(ctx, "tasks/t1/"); err != nil {
+		t.Fatalf("DeletePrefix: %v", err)
+	}
+	if _, err := b.Open(ctx, "tasks/t1/other.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after DeletePrefix = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	l, err := NewLocal(t.TempDir(), "http://localhost:8000/", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testBlob(t, l)
+}
+
+func TestLocalRejectsEscapingKeys(t *testing.T) {
+	root := t.TempDir()
+	l, err := NewLocal(root, "", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := l.path("../../etc/passwd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(p, root) {
+		t.Errorf("path %q escapes root %q", p, root)
+	}
+	if _, err := l.path(".."); err == nil {
+		t.Error("expected an error for a key resolving to the root")
+	}
+}
+
+func TestLocalSignedURL(t *testing.T) {
+	l, err := NewLocal(t.TempDir(), "http://localhost:8000", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := l.SignedURL(context.Background(), "tasks/t1/image.png", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Path != "/files/tasks/t1/image.png" {
+		t.Errorf("path = %q", u.Path)
+	}
+	expires, sig := u.Query().Get("expires"), u.Query().Get("signature")
+
+	if err := l.Verify("tasks/t1/image.png", expires, sig); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if err := l.Verify("tasks/t2/image.png", expires, sig); err == nil {
+		t.Error("signature accepted for another key")
+	}
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	if err := l.Verify("tasks/t1/image.png", past, l.sign("tasks/t1/image.png", past)); err == nil {
+		t.Error("expired link accepted")
+	}
+}
+
+// TestS3 runs against an S3-compatible server such as a local MinIO, e.g.
+//
+//	docker run -p 9000:9000 minio/minio server /data
+//	S3_TEST_ENDPOINT=localhost:9000 S3_TEST_ACCESS_KEY=minioadmin S3_TEST_SECRET_KEY=minioadmin go test ./pkg/storage
+func TestS3(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT is not set")
+	}
+	bucket := os.Getenv("S3_TEST_BUCKET")
+	if bucket == "" {
+		bucket = "courseworker-test"
+	}
+	s, err := NewS3(context.Background(), S3Config{
+		Endpoint:  endpoint,
+		AccessKey: os.Getenv("S3_TEST_ACCESS_KEY"),
+		SecretKey: os.Getenv("S3_TEST_SECRET_KEY"),
+		Bucket:    bucket,
+		Region:    os.Getenv("S3_TEST_REGION"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testBlob(t, s)
+}